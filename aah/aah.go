@@ -21,9 +21,11 @@ import (
 )
 
 const (
-	permRWXRXRX  = 0755
-	permRWRWRW   = 0666
-	importPrefix = "aahframework.org"
+	permRWXRXRX       = 0755
+	permRWRWRW        = 0666
+	importPrefix      = "aahframework.org"
+	buildCacheDirName = ".cache"
+	buildCoverDirName = ".cover"
 )
 
 var (
@@ -108,7 +110,13 @@ func main() {
 		newCmd,
 		runCmd,
 		buildCmd,
+		deployCmd,
+		vfsCmd,
 		listCmd,
+		routesCmd,
+		lintCmd,
+		checkCmd,
+		i18nCmd,
 		cleanCmd,
 		switchCmd,
 		updateCmd,
@@ -0,0 +1,311 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tdewolff/minify"
+	"github.com/tdewolff/minify/css"
+	"github.com/tdewolff/minify/html"
+	"github.com/tdewolff/minify/js"
+	"github.com/tdewolff/minify/svg"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+const (
+	assetsManifestFile     = "manifest.json"
+	assetsDefaultURLPrefix = "/static"
+	assetsHashLen          = 8
+)
+
+var (
+	assetsDefaultFingerprintExcludes = []string{"robots.txt", "humans.txt", "favicon.ico", "*.html", "*.map"}
+
+	cssURLRegex = regexp.MustCompile(`url\(\s*(['"]?)([^'")]+)(['"]?)\s*\)`)
+)
+
+// assets holds the processed static files info of asset pipeline.
+//
+//	build {
+//		assets {
+//			enable = true
+//			dir = "static"
+//			url_prefix = "/static"
+//			minify = true
+//			fingerprint = true
+//			fingerprint_excludes = ["robots.txt", "favicon.ico", "*.html"]
+//		}
+//	}
+type assets struct {
+	Dir       string // relative to app base dir
+	SrcDir    string
+	OutDir    string
+	URLPrefix string
+	Manifest  map[string]string
+}
+
+type assetFile struct {
+	Name string // relative to assets dir, slash separated
+	Path string
+	Info os.FileInfo
+}
+
+// processAssets method runs the asset pipeline if 'build.assets.enable' is
+// true. Minified files are written into build cache directory with original
+// and fingerprinted names, so unchanged files retain its modification time
+// between builds.
+func processAssets(projectCfg *config.Config) *assets {
+	if !projectCfg.BoolDefault("build.assets.enable", false) {
+		return nil
+	}
+
+	appBaseDir := aah.AppBaseDir()
+	dirName := filepath.Clean(filepath.FromSlash(projectCfg.StringDefault("build.assets.dir", "static")))
+	if filepath.IsAbs(dirName) || dirName == "." || dirName == ".." ||
+		strings.HasPrefix(dirName, ".."+string(filepath.Separator)) {
+		logFatalf("build.assets.dir: '%s' must be a directory path relative to application base directory", dirName)
+	}
+
+	// Output directory retains the relative path of assets directory, so
+	// nested directory (e.g. 'public/static') is placed back at same path
+	a := &assets{
+		Dir:       dirName,
+		SrcDir:    filepath.Join(appBaseDir, dirName),
+		OutDir:    filepath.Join(appBaseDir, "build", buildCacheDirName, "assets", dirName),
+		URLPrefix: assetsURLPrefix(projectCfg),
+		Manifest:  make(map[string]string),
+	}
+	if !ess.IsFileExists(a.SrcDir) {
+		logFatalf("build.assets.dir: directory '%s' does not exists", a.SrcDir)
+	}
+
+	cliLog.Infof("Asset pipeline starts for '%s'", a.SrcDir)
+
+	excludes, _ := projectCfg.StringList("build.excludes")
+	files, err := collectAssetFiles(a.SrcDir, ess.Excludes(excludes))
+	if err != nil {
+		logFatal(err)
+	}
+
+	var m *minify.M
+	if projectCfg.BoolDefault("build.assets.minify", true) {
+		m = newMinifier()
+	}
+
+	fingerprint := projectCfg.BoolDefault("build.assets.fingerprint", true)
+	fpExcludes, found := projectCfg.StringList("build.assets.fingerprint_excludes")
+	if !found {
+		fpExcludes = assetsDefaultFingerprintExcludes
+	}
+	fpSkipList := ess.Excludes(fpExcludes)
+	if err = fpSkipList.Validate(); err != nil {
+		logFatalf("build.assets.fingerprint_excludes: %s", err)
+	}
+
+	// CSS files are processed at last, so its url() references can be
+	// rewritten to fingerprinted names
+	sort.SliceStable(files, func(i, j int) bool {
+		return !isCSSFile(files[i].Name) && isCSSFile(files[j].Name)
+	})
+
+	written := make(map[string]bool)
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f.Path)
+		if err != nil {
+			logFatal(err)
+		}
+
+		if isCSSFile(f.Name) {
+			b = rewriteCSSURLs(b, path.Dir(f.Name), a.Manifest)
+		}
+
+		if m != nil {
+			if mb, err := minifyAsset(m, f.Name, b); err != nil {
+				cliLog.Warnf("Unable to minify '%s', using as-is: %s", f.Name, err)
+			} else if mb != nil {
+				b = mb
+			}
+		}
+
+		// file is written with its original name along with fingerprinted
+		// copy, so plain references (hard-coded links, JS imports, source
+		// maps) keep working
+		names := []string{f.Name}
+		if fingerprint && !fpSkipList.Match(path.Base(f.Name)) {
+			name := fingerprintName(f.Name, b)
+			a.Manifest[f.Name] = name
+			names = append(names, name)
+		}
+
+		for _, name := range names {
+			dst := filepath.Join(a.OutDir, filepath.FromSlash(name))
+			if err = writeAssetFile(dst, b); err != nil {
+				logFatal(err)
+			}
+			written[dst] = true
+		}
+	}
+
+	removeStaleAssetFiles(a.OutDir, written)
+
+	mb, _ := json.MarshalIndent(a.Manifest, "", "  ")
+	manifestFile := filepath.Join(appBaseDir, "build", buildCacheDirName, "assets", assetsManifestFile)
+	if err = ioutil.WriteFile(manifestFile, mb, permRWRWRW); err != nil {
+		logFatal(err)
+	}
+
+	cliLog.Infof("Asset pipeline successful, processed %d file(s), manifest: %s", len(files), manifestFile)
+	return a
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func assetsURLPrefix(projectCfg *config.Config) string {
+	return "/" + strings.Trim(projectCfg.StringDefault("build.assets.url_prefix", assetsDefaultURLPrefix), "/")
+}
+
+func collectAssetFiles(srcDir string, skipList ess.Excludes) ([]*assetFile, error) {
+	if err := skipList.Validate(); err != nil {
+		return nil, err
+	}
+
+	var files []*assetFile
+	err := ess.Walk(srcDir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if skipList.Match(info.Name()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(srcDir, fpath)
+		if err != nil {
+			return err
+		}
+		files = append(files, &assetFile{Name: filepath.ToSlash(rel), Path: fpath, Info: info})
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, err
+}
+
+func newMinifier() *minify.M {
+	m := minify.New()
+	m.AddFunc("text/css", css.Minify)
+	m.AddFunc("text/html", html.Minify)
+	m.AddFunc("image/svg+xml", svg.Minify)
+	m.AddFunc("application/javascript", js.Minify)
+	return m
+}
+
+// minifyAsset method returns the minified content if file type is supported
+// otherwise nil. Already minified files (*.min.js, *.min.css) are skipped.
+func minifyAsset(m *minify.M, name string, b []byte) ([]byte, error) {
+	if strings.Contains(path.Base(name), ".min.") {
+		return nil, nil
+	}
+
+	var mediaType string
+	switch strings.ToLower(path.Ext(name)) {
+	case ".css":
+		mediaType = "text/css"
+	case ".js":
+		mediaType = "application/javascript"
+	case ".svg":
+		mediaType = "image/svg+xml"
+	case ".html", ".htm":
+		mediaType = "text/html"
+	default:
+		return nil, nil
+	}
+	return m.Bytes(mediaType, b)
+}
+
+// fingerprintName method returns the content-hashed name of given file name
+// e.g. 'css/aah.css' => 'css/aah.3f2a1b9c.css'.
+func fingerprintName(name string, b []byte) string {
+	sum := sha256.Sum256(b)
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:])[:assetsHashLen] + ext
+}
+
+// rewriteCSSURLs method rewrites the relative url() references of CSS file
+// to fingerprinted names using manifest.
+func rewriteCSSURLs(b []byte, cssDir string, manifest map[string]string) []byte {
+	return cssURLRegex.ReplaceAllFunc(b, func(m []byte) []byte {
+		sm := cssURLRegex.FindSubmatch(m)
+		ref := string(sm[2])
+		if strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "/") ||
+			strings.HasPrefix(ref, "#") || strings.Contains(ref, "://") {
+			return m
+		}
+
+		refPath, suffix := ref, ""
+		if idx := strings.IndexAny(ref, "?#"); idx > -1 {
+			refPath, suffix = ref[:idx], ref[idx:]
+		}
+
+		fp, found := manifest[path.Join(cssDir, refPath)]
+		if !found {
+			return m
+		}
+
+		newRef := path.Join(path.Dir(refPath), path.Base(fp)) + suffix
+		return []byte("url(" + string(sm[1]) + newRef + string(sm[3]) + ")")
+	})
+}
+
+// writeAssetFile method writes the file only if content differs from
+// existing one, it keeps the modification time for unchanged files.
+func writeAssetFile(dst string, b []byte) error {
+	if eb, err := ioutil.ReadFile(dst); err == nil && bytes.Equal(eb, b) {
+		return nil
+	}
+
+	if err := ess.MkDirAll(filepath.Dir(dst), permRWXRXRX); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, b, permRWRWRW)
+}
+
+func removeStaleAssetFiles(outDir string, written map[string]bool) {
+	var stale []string
+	_ = ess.Walk(outDir, func(fpath string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && !written[fpath] {
+			stale = append(stale, fpath)
+		}
+		return nil
+	})
+	ess.DeleteFiles(stale...)
+}
+
+func isCSSFile(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".css")
+}
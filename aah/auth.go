@@ -0,0 +1,392 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// scaffoldAuth holds the auth scheme info of 'generate auth'.
+type scaffoldAuth struct {
+	Scheme        string // auth scheme key name e.g. 'form_auth'
+	Type          string // auth scheme type e.g. 'form'
+	Prefix        string
+	File          string
+	Authenticator string
+	Principal     string
+	Authorizer    string
+}
+
+var authSchemeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func generateAuthAction(c *cli.Context) error {
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+
+	schemeType := strings.ToLower(firstNonEmpty(c.String("t"), c.String("type"), authForm))
+	sa, err := newScaffoldAuth(firstNonEmpty(c.String("s"), c.String("scheme"), schemeType+"_auth"), schemeType)
+	if err != nil {
+		logFatal(err)
+	}
+
+	// authenticator, principal provider and authorizer
+	destFile := filepath.Join(aah.AppBaseDir(), "app", "security", sa.File)
+	if checkAndConfirmOverwrite(c, destFile) {
+		return nil
+	}
+	if err = generateScaffoldSource(destFile, aahAuthTemplate, sa); err != nil {
+		logFatal(err)
+	}
+	cliLog.Infof("Generated auth scheme '%s' providers at %s", sa.Scheme, stripGoSrcPath(destFile))
+
+	// security.conf wiring
+	buf := &bytes.Buffer{}
+	if err = renderTmpl(buf, aahAuthSchemeConfTemplate, sa); err != nil {
+		logFatal(err)
+	}
+	securityFile := filepath.Join(aah.AppBaseDir(), "config", "security.conf")
+	added, err := addAuthScheme(securityFile, sa.Scheme, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+	if err != nil {
+		logFatalf("Unable to add auth scheme into 'config/security.conf': %s", err)
+	}
+	if added {
+		cliLog.Infof("Auth scheme '%s' added into 'config/security.conf'", sa.Scheme)
+	} else {
+		cliLog.Warnf("Auth scheme '%s' already exists in 'config/security.conf', skipped", sa.Scheme)
+	}
+
+	// example protected routes
+	isWeb := ess.IsFileExists(filepath.Join(aah.AppBaseDir(), "views"))
+	ctrl, err := sa.securedController(isWeb)
+	if err != nil {
+		logFatal(err)
+	}
+	ctrlFile := filepath.Join(aah.AppBaseDir(), "app", "controllers", ctrl.File)
+	if ess.IsFileExists(ctrlFile) {
+		cliLog.Infof("Controller %s already exists, skipped", stripGoSrcPath(ctrlFile))
+	} else {
+		if err = generateScaffoldSource(ctrlFile, aahControllerTemplate, ctrl); err != nil {
+			logFatal(err)
+		}
+		cliLog.Infof("Generated controller '%s' at %s", ctrl.Ref, stripGoSrcPath(ctrlFile))
+		if isWeb {
+			ctrl.generateViews(aah.AppConfig())
+		}
+	}
+
+	var entries []*routeEntry
+	for _, e := range ctrl.routeEntries() {
+		e.Attrs = append(e.Attrs, [2]string{"auth", sa.Scheme})
+		entries = append(entries, e)
+	}
+	routesFile := filepath.Join(aah.AppBaseDir(), "config", "routes.conf")
+	domain := firstNonEmpty(c.String("d"), c.String("domain"))
+	if err = addRoutes(routesFile, domain, entries); err != nil {
+		logFatalf("Unable to add routes into 'config/routes.conf': %s", err)
+	}
+
+	verifyScaffold(projectCfg, "controllers", aah.AppRouter().RegisteredActions(), libImportPath("aah"), ctrl)
+
+	cliLog.Infof("What's next, implement the TODO(s) in %s and refer to "+
+		"https://docs.aahframework.org/security-design.html", stripGoSrcPath(destFile))
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// newScaffoldAuth method creates the auth scheme info from scheme name e.g.
+// 'form_auth' and type 'form', 'basic', 'generic' or 'oauth2'.
+func newScaffoldAuth(scheme, schemeType string) (*scaffoldAuth, error) {
+	switch schemeType {
+	case authForm, authBasic, authGeneric, authOAuth2:
+	default:
+		return nil, fmt.Errorf("unsupported auth scheme type '%s', try one of these 'form', 'basic', 'generic', 'oauth2'", schemeType)
+	}
+
+	scheme = strings.TrimSpace(scheme)
+	if !authSchemeNameRegex.MatchString(scheme) {
+		return nil, fmt.Errorf("auth scheme name '%s' is not valid, use lowercase letters, digits and underscore", scheme)
+	}
+
+	prefix := camelIdent(vreplace.Replace(scheme), true)
+	return &scaffoldAuth{
+		Scheme:        scheme,
+		Type:          schemeType,
+		Prefix:        prefix,
+		File:          scheme + ".go",
+		Authenticator: prefix + "AuthenticationProvider",
+		Principal:     prefix + "PrincipalProvider",
+		Authorizer:    prefix + "AuthorizationProvider",
+	}, nil
+}
+
+// securedController method creates the example controller of auth scheme
+// with routes '/<prefix>/secured' and '/<prefix>/secured/admin'.
+func (sa *scaffoldAuth) securedController(isWeb bool) (*scaffoldController, error) {
+	ctrl, err := newScaffoldController(sa.Prefix+"Secured", []string{"index", "admin"})
+	if err != nil {
+		return nil, err
+	}
+
+	ctrl.Path = "/" + path.Join(snakeCase(sa.Prefix), "secured")
+	reply := "c.Reply().JSON"
+	if isWeb {
+		reply = "c.Reply().HTML"
+	}
+	for _, a := range ctrl.Actions {
+		a.Path = ctrl.Path
+		a.Body = fmt.Sprintf("%s(aah.Data{\"principal\": c.Subject().PrimaryPrincipal()})", reply)
+		if a.Name == "Admin" {
+			a.Path += "/admin"
+			a.Body = "if !c.Subject().HasRole(\"admin\") {\n" +
+				"c.Reply().Forbidden().Text(\"403 Forbidden\")\n" +
+				"return\n}\n" + a.Body
+		}
+	}
+	return ctrl, nil
+}
+
+// addAuthScheme method inserts the auth scheme block into 'auth_schemes'
+// section of 'security.conf', sections are created if not exists. Existing
+// content and formatting is kept as-is. It returns false if auth scheme
+// already exists.
+func addAuthScheme(securityFile, scheme string, block []string) (bool, error) {
+	var content string
+	if ess.IsFileExists(securityFile) {
+		b, err := ioutil.ReadFile(securityFile)
+		if err != nil {
+			return false, err
+		}
+		content = string(b)
+	}
+	keys, err := scanConf(content)
+	if err != nil {
+		return false, err
+	}
+	if keys.Find("security.auth_schemes."+scheme) != nil {
+		return false, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	sectionKey := keys.Find("security.auth_schemes")
+	if sectionKey == nil {
+		sectionKey = keys.Find("security")
+		block = wrapConfBlock("auth_schemes", block)
+	}
+	if sectionKey == nil {
+		block = wrapConfBlock("security", block)
+		result := append(strings.Split(strings.TrimRight(content, "\n"), "\n"), "")
+		if ess.IsStrEmpty(strings.TrimSpace(content)) {
+			result = nil
+		}
+		result = append(append(result, indentConfBlock(block, "", "  ")...), "")
+		if err = ess.MkDirAll(filepath.Dir(securityFile), permRWXRXRX); err != nil {
+			return false, err
+		}
+		return true, ioutil.WriteFile(securityFile, []byte(strings.Join(result, "\n")), permRWRWRW)
+	}
+	if !sectionKey.Section || sectionKey.EndLine <= sectionKey.Line {
+		return false, fmt.Errorf("'%s' section is not multi-line", sectionKey.Path)
+	}
+
+	closeLine := lines[sectionKey.EndLine-1]
+	indent := closeLine[:len(closeLine)-len(strings.TrimLeft(closeLine, " \t"))]
+	unit := "  "
+	if strings.Contains(indent, "\t") || strings.Contains(content, "\n\t") {
+		unit = "\t"
+	}
+
+	added := append([]string{""}, indentConfBlock(block, indent+unit, unit)...)
+	if idx := sectionKey.EndLine - 2; idx >= 0 && ess.IsStrEmpty(lines[idx]) {
+		added = added[1:]
+	}
+
+	result := append([]string{}, lines[:sectionKey.EndLine-1]...)
+	result = append(result, added...)
+	result = append(result, lines[sectionKey.EndLine-1:]...)
+	return true, ioutil.WriteFile(securityFile, []byte(strings.Join(result, "\n")), permRWRWRW)
+}
+
+// wrapConfBlock method wraps the block lines into section of given name.
+func wrapConfBlock(name string, block []string) []string {
+	wrapped := []string{name + " {"}
+	for _, l := range block {
+		wrapped = append(wrapped, "  "+l)
+	}
+	return append(wrapped, "}")
+}
+
+// indentConfBlock method re-indents the block lines, which are indented with
+// two spaces per level, with given indent and unit.
+func indentConfBlock(block []string, indent, unit string) []string {
+	var result []string
+	for _, l := range block {
+		if ess.IsStrEmpty(strings.TrimSpace(l)) {
+			result = append(result, "")
+			continue
+		}
+		trimmed := strings.TrimLeft(l, " ")
+		level := (len(l) - len(trimmed)) / 2
+		result = append(result, indent+strings.Repeat(unit, level)+trimmed)
+	}
+	return result
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Auth Templates
+//___________________________________
+
+const aahAuthTemplate = `// Generated by 'aah generate auth', feel free to customize it.
+
+package security
+
+import (
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/security.v0/authc"
+	"aahframework.org/security.v0/authz"
+)
+
+var (
+	_ authc.Authenticator     = (*{{ .Authenticator }})(nil)
+	_ authc.PrincipalProvider = (*{{ .Principal }})(nil)
+	_ authz.Authorizer        = (*{{ .Authorizer }})(nil)
+)
+
+// {{ .Authenticator }} is the authenticator of auth scheme '{{ .Scheme }}'.
+type {{ .Authenticator }} struct {
+}
+
+// Init method initializes the {{ .Authenticator }}, called by aah during
+// application start.
+func (a *{{ .Authenticator }}) Init(appCfg *config.Config) error {
+	// TODO initialize the subject data store e.g. database, LDAP, etc.
+	return nil
+}
+
+// GetAuthenticationInfo method returns the authentication info of subject
+// identified by token, its credential is verified by aah using configured
+// password encoder.
+func (a *{{ .Authenticator }}) GetAuthenticationInfo(authcToken *authc.AuthenticationToken) (*authc.AuthenticationInfo, error) {
+	// TODO look up the subject by 'authcToken.Identity' from data store e.g.
+	//
+	//	authcInfo := authc.NewAuthenticationInfo()
+	//	authcInfo.Principals = append(authcInfo.Principals,
+	//		&authc.Principal{Value: user.Email, IsPrimary: true, Realm: "database"})
+	//	authcInfo.Credential = []byte(user.Password)
+	//	return authcInfo, nil
+	return nil, authc.ErrSubjectNotExists
+}
+
+// {{ .Principal }} is the principal provider of auth scheme '{{ .Scheme }}'.
+type {{ .Principal }} struct {
+}
+
+// Init method initializes the {{ .Principal }}, called by aah during
+// application start.
+func (p *{{ .Principal }}) Init(appCfg *config.Config) error {
+	return nil
+}
+
+// Principal method returns the principals of subject for given key name
+// and its value e.g. OAuth2 token.
+func (p *{{ .Principal }}) Principal(keyName string, v ess.Valuer) ([]*authc.Principal, error) {
+	// TODO look up the subject by given key from data store or provider.
+	return nil, authc.ErrSubjectNotExists
+}
+
+// {{ .Authorizer }} is the authorizer of auth scheme '{{ .Scheme }}'.
+type {{ .Authorizer }} struct {
+}
+
+// Init method initializes the {{ .Authorizer }}, called by aah during
+// application start.
+func (a *{{ .Authorizer }}) Init(appCfg *config.Config) error {
+	return nil
+}
+
+// GetAuthorizationInfo method returns the roles and permissions of
+// authenticated subject.
+func (a *{{ .Authorizer }}) GetAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *authz.AuthorizationInfo {
+	authzInfo := authz.NewAuthorizationInfo()
+	// TODO add roles and permissions of subject from data store e.g.
+	//
+	//	authzInfo.AddRole("admin").AddPermissionString("users:manage:*")
+	return authzInfo
+}
+`
+
+const aahAuthSchemeConfTemplate = `# Auth scheme '{{ .Scheme }}' generated by 'aah generate auth'
+{{ .Scheme }} {
+  scheme = "{{ .Type }}"
+  authenticator = "security/{{ .Authenticator }}"
+  principal = "security/{{ .Principal }}"
+  authorizer = "security/{{ .Authorizer }}"
+{{- if eq .Type "form" }}
+  password_encoder = "bcrypt"
+
+  field {
+    identity = "username"
+    credential = "password"
+  }
+
+  url {
+    login = "/login.html"
+    login_submit = "/login"
+    login_failure = "/login.html?error=true"
+    default_target = "/"
+    always_to_default = false
+  }
+{{- else if eq .Type "basic" }}
+  password_encoder = "bcrypt"
+  realm_name = "Authentication Required"
+{{- else if eq .Type "generic" }}
+  password_encoder = "bcrypt"
+
+  header {
+    identity = "Authorization"
+    credential = ""
+  }
+{{- else if eq .Type "oauth2" }}
+
+  client {
+    id = ""
+    secret = ""
+
+    provider {
+      url {
+        auth = ""
+        token = ""
+      }
+    }
+  }
+
+  url {
+    login = "/{{ .Scheme }}/login"
+    redirect = "/{{ .Scheme }}/callback"
+    success = "/"
+  }
+
+  scopes = []
+{{- end }}
+}
+`
@@ -5,8 +5,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -32,7 +34,8 @@ var buildCmd = cli.Command{
     aah build  OR  aah b
 		aah build --single  OR  aah b -s
     aah build -i github.com/user/appname -o /Users/jeeva
-		aah build -i github.com/user/appname -o /Users/jeeva/aahwebsite.zip`,
+		aah build -i github.com/user/appname -o /Users/jeeva/aahwebsite.zip
+		aah build --format json`,
 	Action: buildAction,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -47,6 +50,7 @@ var buildCmd = cli.Command{
 			Name:  "s, single",
 			Usage: "Creates aah single application binary",
 		},
+		diagFormatFlag,
 	},
 }
 
@@ -62,29 +66,31 @@ func buildAction(c *cli.Context) error {
 	cliLog.Infof("Loaded aah project file: %s", filepath.Join(aah.AppBaseDir(), aahProjectIdentifier))
 	cliLog.Infof("Build starts for '%s' [%s]", aah.AppName(), aah.AppImportPath())
 
+	appAssets := processAssets(projectCfg)
 	if c.Bool("s") || c.Bool("single") {
-		buildSingleBinary(c, projectCfg)
+		buildSingleBinary(c, projectCfg, appAssets)
 	} else {
-		buildBinary(c, projectCfg)
+		buildBinary(c, projectCfg, appAssets)
 	}
 
 	return nil
 }
 
-func buildBinary(c *cli.Context, projectCfg *config.Config) {
+func buildBinary(c *cli.Context, projectCfg *config.Config, appAssets *assets) {
 	appBaseDir := aah.AppBaseDir()
-	processVFSConfig(projectCfg, false)
+	processVFSConfig(projectCfg, false, nil)
 
 	appBinary, err := compileApp(&compileArgs{
 		Cmd:        "BuildCmd",
 		ProjectCfg: projectCfg,
 		AppPack:    true,
+		AppAssets:  appAssets,
 	})
 	if err != nil {
-		logFatal(err)
+		logFatalDiag(c, err)
 	}
 
-	buildBaseDir, err := copyFilesToWorkingDir(projectCfg, appBaseDir, appBinary)
+	buildBaseDir, err := copyFilesToWorkingDir(projectCfg, appBaseDir, appBinary, appAssets)
 	if err != nil {
 		logFatal(err)
 	}
@@ -100,9 +106,9 @@ func buildBinary(c *cli.Context, projectCfg *config.Config) {
 	cliLog.Infof("Application artifact is here: %s\n", destArchiveFile)
 }
 
-func buildSingleBinary(c *cli.Context, projectCfg *config.Config) {
+func buildSingleBinary(c *cli.Context, projectCfg *config.Config, appAssets *assets) {
 	cliLog.Infof("Embed starts for '%s' [%s]", aah.AppName(), aah.AppImportPath())
-	processVFSConfig(projectCfg, true)
+	processVFSConfig(projectCfg, true, appAssets)
 	cliLog.Infof("Embed successful for '%s' [%s]", aah.AppName(), aah.AppImportPath())
 
 	appBinary, err := compileApp(&compileArgs{
@@ -110,9 +116,10 @@ func buildSingleBinary(c *cli.Context, projectCfg *config.Config) {
 		ProjectCfg: projectCfg,
 		AppPack:    true,
 		AppEmbed:   true,
+		AppAssets:  appAssets,
 	})
 	if err != nil {
-		logFatal(err)
+		logFatalDiag(c, err)
 	}
 
 	// Creating app archive
@@ -125,40 +132,107 @@ func buildSingleBinary(c *cli.Context, projectCfg *config.Config) {
 	cliLog.Infof("Application artifact is here: %s\n", destArchiveFile)
 }
 
-func processVFSConfig(projectCfg *config.Config, mode bool) {
+func processVFSConfig(projectCfg *config.Config, mode bool, appAssets *assets) {
 	appBaseDir := aah.AppBaseDir()
-	cleanupAutoGenVFSFiles(appBaseDir)
 
 	excludes, _ := projectCfg.StringList("build.excludes")
-	noGzipList, _ := projectCfg.StringList("vfs.no_gzip")
+	noCompressList, found := projectCfg.StringList("vfs.no_compress")
+	if !found {
+		if noCompressList, found = projectCfg.StringList("vfs.no_gzip"); found {
+			// DEPRECATED
+			cliLog.Warn("DEPRECATED: Config 'vfs.no_gzip' is deprecated, use 'vfs.no_compress' instead. It supports file suffix and MIME type e.g. 'image/*'.")
+		}
+	}
+
+	defaultCompress, err := vfsCompressConfig(projectCfg, "vfs.compress", &vfsCompress{
+		Codecs:     []string{codecGzip},
+		MinSize:    defaultGzipMinSize,
+		NoCompress: noCompressList,
+	})
+	if err != nil {
+		logFatal(err)
+	}
+
+	// Asset pipeline output is embedded in place of its source directory,
+	// it applies to any mount point that contains the source directory
+	var overlays map[string]string
+	if appAssets != nil {
+		overlays = map[string]string{filepath.ToSlash(appAssets.SrcDir): filepath.ToSlash(appAssets.OutDir)}
+	}
 
+	var generated []string
 	if mode {
+		// Generated VFS source embeds the mount blob via 'go:embed'
+		checkGoVersion("1.16", "Single binary build")
+
 		// Default mount point
-		if err := processMount(mode, appBaseDir, "/app", appBaseDir, ess.Excludes(excludes), noGzipList); err != nil {
+		outputs, err := processMount(mode, appBaseDir, "/app", appBaseDir, ess.Excludes(excludes), defaultCompress, overlays)
+		if err != nil {
 			logFatal(err)
 		}
+		generated = append(generated, outputs...)
 	}
 
 	// Custom mount points
 	mountKeys := projectCfg.KeysByPath("vfs.mount")
 	for _, key := range mountKeys {
-		vroot := projectCfg.StringDefault("vfs.mount."+key+".mount_path", "")
-		proot := projectCfg.StringDefault("vfs.mount."+key+".physical_path", "")
+		keyPath := "vfs.mount." + key
+		vroot := projectCfg.StringDefault(keyPath+".mount_path", "")
+		if ess.IsStrEmpty(vroot) {
+			logErrorf("%s.mount_path: value is empty, skip mount", keyPath)
+			continue
+		}
 
-		if !filepath.IsAbs(proot) {
-			logErrorf("vfs %s: physical_path is not absolute path, skip mount: %s", proot, vroot)
+		proot, err := resolveMountPath(appBaseDir, projectCfg.StringDefault(keyPath+".physical_path", ""))
+		if err != nil {
+			logErrorf("%s.physical_path: %s, skip mount: %s", keyPath, err, vroot)
 			continue
 		}
 
-		if !ess.IsStrEmpty(vroot) && !ess.IsStrEmpty(proot) {
-			if err := processMount(mode, appBaseDir, vroot, proot, ess.Excludes(excludes), noGzipList); err != nil {
-				logError(err)
-			}
+		compress, err := vfsCompressConfig(projectCfg, keyPath+".compress", defaultCompress)
+		if err != nil {
+			logError(err)
+			continue
+		}
+
+		outputs, err := processMount(mode, appBaseDir, vroot, proot, ess.Excludes(excludes), compress, overlays)
+		if err != nil {
+			logErrorf("%s: %s", keyPath, err)
+			continue
+		}
+		generated = append(generated, outputs...)
+	}
+
+	cleanupStaleVFSFiles(appBaseDir, generated)
+}
+
+// resolveMountPath method expands the environment variables ('${ENV}' or
+// '$ENV') of mount physical path and resolves relative path against
+// application base directory.
+func resolveMountPath(appBaseDir, proot string) (string, error) {
+	if ess.IsStrEmpty(proot) {
+		return "", errors.New("value is empty")
+	}
+
+	var missing []string
+	proot = os.Expand(proot, func(name string) string {
+		value, found := os.LookupEnv(name)
+		if !found {
+			missing = append(missing, name)
 		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable(s) not set: %s", strings.Join(missing, ", "))
+	}
+
+	if !filepath.IsAbs(proot) {
+		proot = filepath.Join(appBaseDir, proot)
 	}
+	return filepath.Clean(proot), nil
 }
 
-func copyFilesToWorkingDir(projectCfg *config.Config, appBaseDir, appBinary string) (string, error) {
+func copyFilesToWorkingDir(projectCfg *config.Config, appBaseDir, appBinary string, appAssets *assets) (string, error) {
 	appBinaryName := filepath.Base(appBinary)
 	tmpDir, err := ioutil.TempDir("", appBinaryName)
 	if err != nil {
@@ -196,6 +270,11 @@ func copyFilesToWorkingDir(projectCfg *config.Config, appBaseDir, appBinary stri
 			continue
 		}
 
+		// asset pipeline output is packaged in place of its source directory
+		if appAssets != nil && srcdir == appAssets.SrcDir {
+			srcdir = appAssets.OutDir
+		}
+
 		if ess.IsFileExists(srcdir) {
 			if err = ess.CopyDir(buildBaseDir, srcdir, subTreeExcludes); err != nil {
 				if !strings.HasSuffix(err.Error(), "/bin") {
@@ -205,6 +284,20 @@ func copyFilesToWorkingDir(projectCfg *config.Config, appBaseDir, appBinary stri
 		}
 	}
 
+	// nested assets directory (e.g. 'public/static') gets copied along with
+	// its parent directory above, replace it with asset pipeline output
+	if appAssets != nil && filepath.Dir(appAssets.Dir) != "." {
+		destDir := filepath.Join(buildBaseDir, appAssets.Dir)
+		if ess.IsFileExists(destDir) {
+			if err = os.RemoveAll(destDir); err != nil {
+				return "", err
+			}
+			if err = ess.CopyDir(filepath.Dir(destDir), appAssets.OutDir, subTreeExcludes); err != nil {
+				return "", err
+			}
+		}
+	}
+
 	return buildBaseDir, err
 }
 
@@ -0,0 +1,505 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// Config value kinds of check schema, enum values are given as
+// 'enum:value1|value2'.
+const (
+	kindString   = "string"
+	kindBool     = "bool"
+	kindInt      = "int"
+	kindList     = "list"
+	kindScalar   = "scalar"
+	kindDuration = "duration"
+)
+
+// checkProjectSchema is the known keys of 'aah.project', '*' matches any
+// single key segment.
+var checkProjectSchema = map[string]string{
+	"name":                              kindString,
+	"build.binary_name":                 kindString,
+	"build.version":                     kindString,
+	"build.flags":                       kindList,
+	"build.ldflags":                     kindString,
+	"build.tags":                        kindString,
+	"build.excludes":                    kindList,
+	"build.ast_excludes":                kindList,
+	"build.dep_get":                     kindBool,
+	"build.log_level":                   "enum:TRACE|DEBUG|INFO|WARN|ERROR|FATAL",
+	"build.main_template":               kindString,
+	"build.main_blocks":                 kindString,
+	"build.views.validate":              kindBool,
+	"build.views.funcs":                 kindList,
+	"build.assets.enable":               kindBool,
+	"build.assets.dir":                  kindString,
+	"build.assets.url_prefix":           kindString,
+	"build.assets.minify":               kindBool,
+	"build.assets.fingerprint":          kindBool,
+	"build.assets.fingerprint_excludes": kindList,
+	"hot_reload.enable":                 kindBool,
+	"hot_reload.watch.dir_excludes":     kindList,
+	"hot_reload.watch.file_excludes":    kindList,
+	"vfs.no_gzip":                       kindList,
+	"vfs.no_compress":                   kindList,
+	"vfs.compress.codecs":               kindList,
+	"vfs.compress.level":                kindInt,
+	"vfs.compress.min_size":             kindInt,
+	"vfs.mount.*.mount_path":            kindString,
+	"vfs.mount.*.physical_path":         kindString,
+	"vfs.mount.*.compress.codecs":       kindList,
+	"vfs.mount.*.compress.level":        kindInt,
+	"vfs.mount.*.compress.min_size":     kindInt,
+	"file.go.upgrade_replacer":          kindList,
+	"file.view.upgrade_replacer":        kindList,
+}
+
+// checkAppSchema is the known keys of 'aah.conf', 'security.conf' and
+// environment profiles. Application may have its own keys, so unknown keys
+// are not reported.
+var checkAppSchema = map[string]string{
+	"name":                                  kindString,
+	"desc":                                  kindString,
+	"instance_name":                         kindString,
+	"pid_file":                              kindString,
+	"env.active":                            kindString,
+	"server.address":                        kindString,
+	"server.port":                           kindScalar,
+	"server.timeout.read":                   kindDuration,
+	"server.timeout.write":                  kindDuration,
+	"server.timeout.grace_shutdown":         kindDuration,
+	"server.ssl.enable":                     kindBool,
+	"server.ssl.cert":                       kindString,
+	"server.ssl.key":                        kindString,
+	"server.ssl.lets_encrypt.enable":        kindBool,
+	"server.ssl.lets_encrypt.host_policy":   kindList,
+	"server.access_log.enable":              kindBool,
+	"server.dump_log.enable":                kindBool,
+	"request.max_body_size":                 kindString,
+	"request.multipart_size":                kindString,
+	"i18n.default":                          kindString,
+	"format.date":                           kindString,
+	"format.datetime":                       kindString,
+	"runtime.debug.stack_buffer_size":       kindString,
+	"runtime.debug.all_goroutines":          kindBool,
+	"render.default":                        kindString,
+	"render.pretty":                         kindBool,
+	"render.gzip.enable":                    kindBool,
+	"render.gzip.level":                     kindInt,
+	"view.engine":                           kindString,
+	"view.ext":                              kindString,
+	"view.delimiters":                       kindString,
+	"view.default_layout":                   kindBool,
+	"view.case_sensitive":                   kindBool,
+	"view.default_frame":                    kindString,
+	"log.receiver":                          "enum:console|file",
+	"log.level":                             "enum:TRACE|DEBUG|INFO|WARN|ERROR|FATAL",
+	"log.format":                            "enum:text|json",
+	"log.color":                             kindBool,
+	"security.session.mode":                 "enum:stateless|stateful",
+	"security.session.store.type":           kindString,
+	"security.session.ttl":                  kindDuration,
+	"security.session.secure":               kindBool,
+	"security.session.http_only":            kindBool,
+	"security.anti_csrf.enable":             kindBool,
+	"security.auth_schemes.*.scheme":        "enum:form|basic|generic|oauth2",
+	"security.auth_schemes.*.authenticator": kindString,
+	"security.auth_schemes.*.principal":     kindString,
+	"security.auth_schemes.*.authorizer":    kindString,
+	"security.auth_schemes.*.file_realm":    kindString,
+	"security.http_header.enable":           kindBool,
+}
+
+// checker holds the state of check command.
+type checker struct {
+	baseDir string
+	diags   []*diagnostic
+	keys    map[string]confKeys
+}
+
+var checkCmd = cli.Command{
+	Name:  "check",
+	Usage: "Validates aah application configuration and code references without building",
+	Description: `Command check parses and validates the 'aah.project', 'aah.conf', 'routes.conf',
+	'security.conf' and environment profiles under 'config/env'. It reports
+
+		* Config syntax errors, unknown 'aah.project' keys and known keys value types
+		* Auth scheme authenticator, principal and authorizer types not exists
+		* Route targets not implemented and static directory not exists
+		* VFS mount paths not resolvable
+
+	Diagnostics are reported with file and line position, exit code is non-zero
+	if any error found.
+
+	Examples of short and long flags:
+		aah check
+		aah check -i github.com/user/appname
+		aah check --format json`,
+	Action: checkAction,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "i, importpath",
+			Usage: "Import path of aah application",
+		},
+		diagFormatFlag,
+	},
+}
+
+func checkAction(c *cli.Context) error {
+	importPath := appImportPath(c)
+	cliLog = initCLILogger(nil)
+
+	ck := &checker{
+		baseDir: filepath.Join(gosrcDir, filepath.FromSlash(importPath)),
+		keys:    make(map[string]confKeys),
+	}
+	if !isAahProject(filepath.Join(ck.baseDir, aahProjectIdentifier)) {
+		logFatalf("'%s' is not a valid aah application, missing 'aah.project' file", importPath)
+	}
+	cliLog.Infof("Check starts for '%s'", importPath)
+
+	// config files
+	projectCfg := ck.checkConfigFile(aahProjectIdentifier, checkProjectSchema, true)
+	appCfgOk := ck.checkConfigFile(filepath.Join("config", "aah.conf"), checkAppSchema, false) != nil
+	securityCfg := ck.checkConfigFile(filepath.Join("config", "security.conf"), checkAppSchema, false)
+	routesCfgOk := ck.checkConfigFile(filepath.Join("config", "routes.conf"), nil, false) != nil
+	envFiles, _ := filepath.Glob(filepath.Join(ck.baseDir, "config", "env", "*.conf"))
+	for _, f := range envFiles {
+		rel, _ := filepath.Rel(ck.baseDir, f)
+		ck.checkConfigFile(rel, checkAppSchema, false)
+	}
+
+	if projectCfg != nil {
+		ck.checkVFSMounts(projectCfg)
+	}
+
+	if securityCfg != nil {
+		ck.checkAuthSchemes(securityCfg, filepath.Join("config", "security.conf"))
+	}
+
+	// application level checks requires successful initialize
+	if appCfgOk && routesCfgOk && projectCfg != nil {
+		if err := aah.Init(importPath); err != nil {
+			ck.errorf(filepath.Join("config", "aah.conf"), 0, "application initialize failed: %s", err)
+		} else {
+			ck.checkRoutes(projectCfg)
+		}
+	}
+
+	ck.report(strings.ToLower(c.String("format")))
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (ck *checker) errorf(file string, line int, format string, v ...interface{}) {
+	ck.diags = append(ck.diags, &diagnostic{Origin: diagOriginCheck, File: filepath.ToSlash(file), Line: line,
+		Severity: diagError, Message: fmt.Sprintf(format, v...)})
+}
+
+func (ck *checker) warnf(file string, line int, format string, v ...interface{}) {
+	ck.diags = append(ck.diags, &diagnostic{Origin: diagOriginCheck, File: filepath.ToSlash(file), Line: line,
+		Severity: diagWarning, Message: fmt.Sprintf(format, v...)})
+}
+
+func (ck *checker) line(file, keyPath string) int {
+	return ck.keys[filepath.ToSlash(file)].Line(keyPath)
+}
+
+// checkConfigFile method parses the config file and validates its keys
+// against schema, it returns nil if file does not exists or parse fails.
+func (ck *checker) checkConfigFile(file string, schema map[string]string, reportUnknown bool) *config.Config {
+	absFile := filepath.Join(ck.baseDir, file)
+	if !ess.IsFileExists(absFile) {
+		if file == aahProjectIdentifier || filepath.Base(file) == "aah.conf" || filepath.Base(file) == "routes.conf" {
+			ck.errorf(file, 0, "file does not exists")
+		}
+		return nil
+	}
+
+	keys, err := scanConfFile(absFile)
+	if err != nil {
+		ck.errorf(file, 0, "%s", err)
+	}
+	ck.keys[filepath.ToSlash(file)] = keys
+
+	cfg, err := config.LoadFile(absFile)
+	if err != nil {
+		ck.errorf(file, 0, "syntax error: %s", err)
+		return nil
+	}
+
+	if schema == nil {
+		return cfg
+	}
+
+	envPrefix := ""
+	if strings.HasPrefix(filepath.ToSlash(file), "config/env/") {
+		envPrefix = "env." + ess.StripExt(filepath.Base(file)) + "."
+	}
+
+	for _, k := range keys.Leaves() {
+		keyPath := strings.TrimPrefix(k.Path, envPrefix)
+		kind, found := matchSchemaKey(schema, keyPath)
+		if !found {
+			if reportUnknown {
+				ck.warnf(file, k.Line, "%s: unknown key", k.Path)
+			}
+			continue
+		}
+
+		value, _ := cfg.Get(k.Path)
+		if err := checkValueKind(value, kind); err != nil {
+			ck.errorf(file, k.Line, "%s: %s", k.Path, err)
+		}
+	}
+	return cfg
+}
+
+func (ck *checker) checkVFSMounts(projectCfg *config.Config) {
+	for _, key := range projectCfg.KeysByPath("vfs.mount") {
+		keyPath := "vfs.mount." + key
+		vroot := projectCfg.StringDefault(keyPath+".mount_path", "")
+		if !strings.HasPrefix(vroot, "/") {
+			ck.errorf(aahProjectIdentifier, ck.line(aahProjectIdentifier, keyPath+".mount_path"),
+				"%s.mount_path: value must be absolute virtual path e.g. '/assets', found '%s'", keyPath, vroot)
+		}
+
+		proot, err := resolveMountPath(ck.baseDir, projectCfg.StringDefault(keyPath+".physical_path", ""))
+		if err == nil && !ess.IsFileExists(proot) {
+			err = fmt.Errorf("path '%s' does not exists", proot)
+		}
+		if err != nil {
+			ck.errorf(aahProjectIdentifier, ck.line(aahProjectIdentifier, keyPath+".physical_path"),
+				"%s.physical_path: %s", keyPath, err)
+		}
+	}
+}
+
+// checkAuthSchemes method checks the authenticator, principal and authorizer
+// types referred in auth schemes are exists in the Go source.
+func (ck *checker) checkAuthSchemes(cfg *config.Config, file string) {
+	keyPrefix := "security.auth_schemes"
+	for _, scheme := range cfg.KeysByPath(keyPrefix) {
+		keyPath := keyPrefix + "." + scheme
+		if cfg.StringDefault(keyPath+".scheme", "") == "basic" &&
+			!ess.IsStrEmpty(cfg.StringDefault(keyPath+".file_realm", "")) {
+			continue
+		}
+
+		for _, name := range []string{"authenticator", "principal", "authorizer"} {
+			typeRef := cfg.StringDefault(keyPath+"."+name, "")
+			if ess.IsStrEmpty(typeRef) {
+				continue
+			}
+
+			if err := ck.checkTypeExists(typeRef); err != nil {
+				ck.errorf(file, ck.line(file, keyPath+"."+name), "%s.%s: %s", keyPath, name, err)
+			}
+		}
+	}
+}
+
+// checkTypeExists method checks the type reference (e.g.
+// 'security/AuthenticationProvider' or 'github.com/user/pkg/Type') exists.
+// Reference starts with 'security' is resolved within application 'app'
+// directory, same as generated 'aah.go'.
+func (ck *checker) checkTypeExists(typeRef string) error {
+	dir := filepath.Join(gosrcDir, filepath.FromSlash(path.Dir(typeRef)))
+	if strings.HasPrefix(typeRef, "security") {
+		dir = filepath.Join(ck.baseDir, "app", filepath.FromSlash(path.Dir(typeRef)))
+	}
+
+	if !ess.IsFileExists(dir) {
+		return fmt.Errorf("package directory '%s' does not exists", dir)
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	typeName := path.Base(typeRef)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			if obj := f.Scope.Lookup(typeName); obj != nil && obj.Kind == ast.Typ {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("type '%s' not found in '%s'", typeName, dir)
+}
+
+func (ck *checker) checkRoutes(projectCfg *config.Config) {
+	file := filepath.Join("config", "routes.conf")
+	actions, err := inspectAppActions(projectCfg)
+	if err != nil {
+		ck.errorf("app", 0, "%s", err)
+		return
+	}
+
+	routes, err := appRouteTable(actions)
+	if err != nil {
+		ck.errorf(file, 0, "%s", err)
+		return
+	}
+
+	keys := ck.keys[filepath.ToSlash(file)]
+	for _, r := range routes {
+		line := routeLine(keys, r)
+		if r.Static {
+			if dir := staticRouteDir(r); !ess.IsStrEmpty(dir) && !ess.IsFileExists(dir) {
+				ck.errorf(file, line, "route '%s': static path '%s' does not exists", r.Name, dir)
+			}
+			continue
+		}
+
+		if !r.Implemented {
+			ck.errorf(file, line, "route '%s': target %s.%s is not implemented", r.Name, r.Controller, r.Action)
+		}
+	}
+}
+
+func (ck *checker) report(format string) {
+	sortDiagnostics(ck.diags)
+	printDiagnostics(format, ck.diags)
+
+	var errCnt int
+	for _, d := range ck.diags {
+		if d.Severity == diagError {
+			errCnt++
+		}
+	}
+
+	if errCnt > 0 {
+		cliLog.Errorf("Check failed with %d error(s), %d warning(s)", errCnt, len(ck.diags)-errCnt)
+		exit(1)
+	}
+	cliLog.Infof("Check successful, %d warning(s)\n", len(ck.diags))
+}
+
+// routeKeyPath method returns the key path of route in 'routes.conf'.
+func routeKeyPath(keys confKeys, r *routeInfo) string {
+	prefix := "domains." + r.Domain + "."
+	for _, k := range keys {
+		if k.Section && strings.HasPrefix(k.Path, prefix) && path.Ext(k.Path) == "."+r.Name {
+			return k.Path
+		}
+	}
+	return ""
+}
+
+// staticRouteDir method returns the absolute path of static route directory
+// or file, relative path is resolved against application base directory.
+func staticRouteDir(r *routeInfo) string {
+	for _, domain := range aah.AppRouter().Domains {
+		if domain.Key != r.Domain {
+			continue
+		}
+
+		route := domain.LookupByName(r.Name)
+		if route == nil || ess.IsStrEmpty(route.Dir) {
+			return ""
+		}
+
+		p := filepath.Join(route.Dir, route.File)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(aah.AppBaseDir(), p)
+		}
+		return p
+	}
+	return ""
+}
+
+func routeLine(keys confKeys, r *routeInfo) int {
+	if keyPath := routeKeyPath(keys, r); !ess.IsStrEmpty(keyPath) {
+		return keys.Line(keyPath)
+	}
+	return 0
+}
+
+// matchSchemaKey method returns the value kind of key path from schema.
+func matchSchemaKey(schema map[string]string, keyPath string) (string, bool) {
+	if kind, found := schema[keyPath]; found {
+		return kind, true
+	}
+
+	segments := strings.Split(keyPath, ".")
+	for pattern, kind := range schema {
+		if !strings.Contains(pattern, "*") {
+			continue
+		}
+		if matched, _ := path.Match(strings.Replace(pattern, ".", "/", -1),
+			strings.Join(segments, "/")); matched {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// checkValueKind method validates the config value against kind.
+func checkValueKind(value interface{}, kind string) error {
+	switch v := value.(type) {
+	case string:
+		switch {
+		case kind == kindString, kind == kindScalar:
+			return nil
+		case kind == kindDuration:
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("invalid duration value '%s', e.g. '30s', '1m'", v)
+			}
+			return nil
+		case strings.HasPrefix(kind, "enum:"):
+			values := strings.Split(strings.TrimPrefix(kind, "enum:"), "|")
+			for _, ev := range values {
+				if strings.EqualFold(ev, v) {
+					return nil
+				}
+			}
+			return fmt.Errorf("invalid value '%s', supported values are %s", v, strings.Join(values, ", "))
+		}
+	case bool:
+		if kind == kindBool || kind == kindScalar {
+			return nil
+		}
+	case int, int64, float64:
+		if kind == kindInt || kind == kindScalar {
+			return nil
+		}
+	case []interface{}, []string:
+		if kind == kindList {
+			return nil
+		}
+	case nil:
+		return nil
+	}
+
+	if strings.HasPrefix(kind, "enum:") || kind == kindDuration {
+		kind = kindString
+	}
+	return fmt.Errorf("expected %s value, found %T", kind, value)
+}
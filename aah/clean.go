@@ -5,9 +5,12 @@
 package main
 
 import (
+	"path/filepath"
+
 	"gopkg.in/urfave/cli.v1"
 
 	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
 )
 
 var cleanCmd = cli.Command{
@@ -42,6 +45,7 @@ func cleanAction(c *cli.Context) error {
 
 	cleanupAutoGenFiles(aah.AppBaseDir())
 	cleanupAutoGenVFSFiles(aah.AppBaseDir())
+	ess.DeleteFiles(filepath.Join(aah.AppBaseDir(), "build"))
 
 	cliLog.Infof("Import Path '%v' clean successful.\n", importPath)
 
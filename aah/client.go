@@ -0,0 +1,942 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// clientAPI holds the language neutral API model for client generation,
+// it is derived from OpenAPI document of the application.
+type clientAPI struct {
+	AppName    string
+	Package    string
+	Operations []*clientOp
+	Models     []*clientModel
+}
+
+type clientOp struct {
+	Name      string
+	RouteName string
+	Method    string
+	Path      string
+	Doc       string
+	Params    []*clientParam
+	Query     []*clientParam
+	Body      *clientParam
+}
+
+type clientParam struct {
+	Name     string
+	VarName  string
+	In       string
+	Required bool
+	Schema   *apiSchema
+}
+
+type clientModel struct {
+	Name   string
+	Doc    string
+	Fields []*clientField
+	Schema *apiSchema // non-object type e.g. recursive map or slice
+}
+
+type clientField struct {
+	Name     string
+	JSONName string
+	Doc      string
+	Required bool
+	Schema   *apiSchema
+}
+
+// goClientReserved are the identifiers used by generated client method.
+var goClientReserved = map[string]bool{"c": true, "ctx": true, "out": true, "q": true}
+
+var goClientStdPkgs = []string{"bytes", "context", "encoding/json", "fmt", "io", "io/ioutil",
+	"net/http", "net/url", "strings", "time"}
+
+var goInitialisms = map[string]string{"id": "ID", "url": "URL", "uri": "URI", "api": "API",
+	"http": "HTTP", "json": "JSON", "html": "HTML", "ip": "IP", "uuid": "UUID"}
+
+func generateClientAction(c *cli.Context) error {
+	lang := strings.ToLower(strings.TrimSpace(firstNonEmpty(c.String("l"), c.String("lang"))))
+	if ess.IsStrEmpty(lang) {
+		_ = cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	if lang != "go" && lang != "ts" {
+		logFatalf("Unsupported client language '%s', try 'go' or 'ts'", lang)
+	}
+	if c.Bool("static") && lang != "ts" {
+		logFatalf("Flag '--static' is supported only for '--lang ts', Go client is not a static file")
+	}
+
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+	cliLog.Infof("Loaded aah project file: %s\n", filepath.Join(aah.AppBaseDir(), aahProjectIdentifier))
+
+	doc, err := buildOpenAPIDoc(projectCfg)
+	if err != nil {
+		logFatal(err)
+	}
+
+	pkgName := firstNonEmpty(c.String("p"), c.String("package"), goPackageName(aah.AppName())+"client")
+	destDir := firstNonEmpty(c.String("o"), c.String("output"))
+	if c.Bool("static") {
+		destDir = filepath.Join(aah.AppBaseDir(), "static", "js")
+	} else if ess.IsStrEmpty(destDir) {
+		destDir = filepath.Join(aah.AppBaseDir(), "client", pkgName)
+		if lang == "ts" {
+			destDir = filepath.Join(aah.AppBaseDir(), "client", "ts")
+		}
+	}
+	if destDir, err = filepath.Abs(destDir); err != nil {
+		logFatal(err)
+	}
+
+	var api *clientAPI
+	var files map[string][]byte
+	if lang == "ts" {
+		api = newClientAPI(doc, pkgName, tsIdent, tsClientReserved)
+		files, err = generateTSClient(api)
+	} else {
+		api = newClientAPI(doc, pkgName, goIdent, goClientReserved)
+		files, err = generateGoClient(api)
+	}
+	if err != nil {
+		logFatal(err)
+	}
+
+	if err = writeClientFiles(destDir, files); err != nil {
+		logFatal(err)
+	}
+
+	cliLog.Infof("Generated '%s' client '%s' with %d method(s) at '%s'\n", lang, pkgName, len(api.Operations), destDir)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// newClientAPI method creates the client API model from OpenAPI document,
+// identifiers are created with given ident func and reserved identifiers
+// are not used for parameters.
+func newClientAPI(doc *openapiDoc, pkgName string, ident func(string, bool) string, reserved map[string]bool) *clientAPI {
+	api := &clientAPI{AppName: doc.Info.Title, Package: pkgName}
+
+	var paths []string
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	opNames := make(map[string]bool)
+	for _, p := range paths {
+		var methods []string
+		for m := range doc.Paths[p] {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+
+		for _, m := range methods {
+			op := doc.Paths[p][m]
+			cop := &clientOp{
+				Name:      uniqueIdent(ident(op.OperationID, true), opNames),
+				RouteName: op.OperationID,
+				Method:    strings.ToUpper(m),
+				Path:      p,
+				Doc:       op.Description,
+			}
+			if ess.IsStrEmpty(cop.Doc) {
+				cop.Doc = op.Summary
+			}
+
+			varNames := make(map[string]bool)
+			for name := range reserved {
+				varNames[name] = true
+			}
+			for _, param := range op.Parameters {
+				cp := &clientParam{
+					Name:     param.Name,
+					VarName:  uniqueIdent(ident(param.Name, false), varNames),
+					In:       param.In,
+					Required: param.Required,
+					Schema:   param.Schema,
+				}
+				cop.Params = append(cop.Params, cp)
+				if cp.In == "query" {
+					cop.Query = append(cop.Query, cp)
+				}
+			}
+
+			if op.RequestBody != nil {
+				if mt, found := op.RequestBody.Content["application/json"]; found {
+					cop.Body = &clientParam{
+						Name:     "body",
+						VarName:  uniqueIdent(ident(firstNonEmpty(mt.Schema.RefName(), "body"), false), varNames),
+						In:       "body",
+						Required: op.RequestBody.Required,
+						Schema:   mt.Schema,
+					}
+					cop.Params = append(cop.Params, cop.Body)
+				}
+			}
+			api.Operations = append(api.Operations, cop)
+		}
+	}
+
+	if doc.Components != nil {
+		var names []string
+		for name := range doc.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			s := doc.Components.Schemas[name]
+			m := &clientModel{Name: ident(name, true), Doc: s.Description}
+			if s.Type != "object" || s.AdditionalProperties != nil {
+				m.Schema = s
+				api.Models = append(api.Models, m)
+				continue
+			}
+
+			var props []string
+			for p := range s.Properties {
+				props = append(props, p)
+			}
+			sort.Strings(props)
+
+			fieldNames := make(map[string]bool)
+			for _, p := range props {
+				m.Fields = append(m.Fields, &clientField{
+					Name:     uniqueIdent(ident(p, true), fieldNames),
+					JSONName: p,
+					Doc:      s.Properties[p].Description,
+					Required: inStrSlice(s.Required, p),
+					Schema:   s.Properties[p],
+				})
+			}
+			api.Models = append(api.Models, m)
+		}
+	}
+
+	return api
+}
+
+// generateGoClient method generates the Go client package files.
+func generateGoClient(api *clientAPI) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for name, tmpl := range map[string]*template.Template{
+		"client.go":       goClientTmpl,
+		"api.go":          goClientAPITmpl,
+		"models.go":       goClientModelsTmpl,
+		"example_test.go": goClientExampleTmpl,
+	} {
+		if name == "models.go" && len(api.Models) == 0 {
+			continue
+		}
+
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, api); err != nil {
+			return nil, fmt.Errorf("client: %s: %s", name, err)
+		}
+
+		b, err := goClientImports(buf.Bytes())
+		if err == nil {
+			b, err = format.Source(b)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("client: %s: %s", name, err)
+		}
+		files[name] = b
+	}
+	return files, nil
+}
+
+func writeClientFiles(destDir string, files map[string][]byte) error {
+	if err := ess.MkDirAll(destDir, permRWXRXRX); err != nil {
+		return err
+	}
+
+	for name, b := range files {
+		if err := ioutil.WriteFile(filepath.Join(destDir, name), b, permRWRWRW); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goClientImports method fills the import block placeholder of generated
+// source with the standard packages referenced in the source.
+func goClientImports(src []byte) ([]byte, error) {
+	const placeholder = "//aah:imports"
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// package identifiers are not resolved by parser
+	used := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if se, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := se.X.(*ast.Ident); ok && ident.Obj == nil {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var imports []string
+	for _, pkg := range goClientStdPkgs {
+		if used[path.Base(pkg)] {
+			imports = append(imports, fmt.Sprintf("%q", pkg))
+		}
+	}
+
+	var block string
+	if len(imports) > 0 {
+		block = "import (\n" + strings.Join(imports, "\n") + "\n)"
+	}
+	return bytes.Replace(src, []byte(placeholder), []byte(block), 1), nil
+}
+
+// goType method returns the Go type of OpenAPI schema.
+func goType(s *apiSchema) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if !ess.IsStrEmpty(s.Ref) {
+		return "*" + goIdent(s.RefName(), true)
+	}
+
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "time.Time"
+		case "byte":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goType(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + goType(s.AdditionalProperties)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// goZero method returns the Go expression of sample value for schema, it is
+// used in generated examples.
+func goZero(s *apiSchema) string {
+	t := goType(s)
+	switch {
+	case strings.HasPrefix(t, "*"):
+		return "&" + t[1:] + "{}"
+	case t == "string":
+		return `""`
+	case t == "bool":
+		return "false"
+	case t == "time.Time":
+		return "time.Now()"
+	case strings.HasPrefix(t, "int") || strings.HasPrefix(t, "float"):
+		return "0"
+	}
+	return "nil"
+}
+
+// goPathExpr method returns the Go expression of request path, path
+// parameters are escaped.
+func goPathExpr(op *clientOp) string {
+	params := make(map[string]*clientParam)
+	for _, p := range op.Params {
+		if p.In == "path" {
+			params[p.Name] = p
+		}
+	}
+
+	var parts []string
+	literal := ""
+	for _, seg := range strings.SplitAfter(op.Path, "/") {
+		name := strings.TrimSuffix(seg, "/")
+		p, found := params[strings.TrimSuffix(strings.TrimPrefix(name, "{"), "}")]
+		if !found || !strings.HasPrefix(name, "{") {
+			literal += seg
+			continue
+		}
+
+		if !ess.IsStrEmpty(literal) {
+			parts = append(parts, fmt.Sprintf("%q", literal))
+		}
+		v := p.VarName
+		if goType(p.Schema) != "string" {
+			v = "fmt.Sprint(" + v + ")"
+		}
+		parts = append(parts, "url.PathEscape("+v+")")
+		literal = strings.TrimPrefix(seg, name)
+	}
+	if !ess.IsStrEmpty(literal) || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goQueryStmt method returns the Go statement to add query parameter into
+// 'q', optional parameters are added only if it's non-zero value.
+func goQueryStmt(p *clientParam) string {
+	t := goType(p.Schema)
+	var value, cond string
+	switch {
+	case strings.HasPrefix(t, "[]") && t != "[]byte":
+		return fmt.Sprintf("for _, v := range %s {\nq.Add(%q, fmt.Sprint(v))\n}", p.VarName, p.Name)
+	case t == "string":
+		value, cond = p.VarName, p.VarName+` != ""`
+	case t == "bool":
+		value, cond = "fmt.Sprint("+p.VarName+")", p.VarName
+	case t == "time.Time":
+		value, cond = p.VarName+".Format(time.RFC3339)", "!"+p.VarName+".IsZero()"
+	case strings.HasPrefix(t, "int") || strings.HasPrefix(t, "float"):
+		value, cond = "fmt.Sprint("+p.VarName+")", p.VarName+" != 0"
+	default:
+		// remaining types are nil-able i.e. pointer, map, []byte and interface,
+		// nested structs are not listed as query parameters
+		value, cond = "fmt.Sprint("+p.VarName+")", p.VarName+" != nil"
+	}
+
+	stmt := fmt.Sprintf("q.Set(%q, %s)", p.Name, value)
+	if p.Required {
+		return stmt
+	}
+	return fmt.Sprintf("if %s {\n%s\n}", cond, stmt)
+}
+
+// goIdent method returns the Go identifier of name e.g. 'create_user' to
+// 'CreateUser', 'user_id' to 'userID'.
+func goIdent(name string, exported bool) string {
+	ident := camelIdent(name, exported)
+	if token.Lookup(ident).IsKeyword() {
+		ident += "Param"
+	}
+	return ident
+}
+
+func camelIdent(name string, exported bool) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var ident string
+	for i, w := range words {
+		if v, found := goInitialisms[strings.ToLower(w)]; found {
+			if i == 0 && !exported {
+				v = strings.ToLower(v)
+			}
+			ident += v
+			continue
+		}
+		if i == 0 && !exported {
+			ident += strings.ToLower(w[:1]) + w[1:]
+			continue
+		}
+		ident += strings.ToUpper(w[:1]) + w[1:]
+	}
+
+	switch {
+	case ess.IsStrEmpty(ident):
+		ident = "x"
+	case unicode.IsDigit(rune(ident[0])):
+		ident = "x" + ident
+	}
+	if exported {
+		ident = strings.ToUpper(ident[:1]) + ident[1:]
+	}
+	return ident
+}
+
+// goPackageName method returns the valid Go package name of given name.
+func goPackageName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9' && b.Len() > 0) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// uniqueIdent method returns the ident with numeric suffix if it's already
+// taken and marks it as taken.
+func uniqueIdent(ident string, taken map[string]bool) string {
+	unique := ident
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", ident, i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// commentLines method returns the text as Go comment lines.
+func commentLines(text string) string {
+	text = strings.TrimSpace(text)
+	if ess.IsStrEmpty(text) {
+		return ""
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("// "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Go client templates
+//___________________________________
+
+var goClientFuncMap = template.FuncMap{
+	"goType":      goType,
+	"goZero":      goZero,
+	"goPathExpr":  goPathExpr,
+	"goQueryStmt": goQueryStmt,
+	"comment":     commentLines,
+}
+
+var goClientTmpl = template.Must(template.New("client").Funcs(goClientFuncMap).Parse(`// Code generated by aah CLI. DO NOT EDIT.
+
+// Package {{ .Package }} is the Go client of '{{ .AppName }}' API.
+package {{ .Package }}
+
+//aah:imports
+
+// Client is the HTTP client of '{{ .AppName }}' API.
+type Client struct {
+	// BaseURL is the base URL of API e.g. 'https://api.example.com'.
+	BaseURL string
+
+	// HTTPClient is used to send the requests, defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Header is added into every request e.g. 'Authorization'.
+	Header http.Header
+}
+
+// Error is returned by client methods for non-2xx HTTP response.
+type Error struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+// NewClient method creates the API client for given base URL.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+		Header:     make(http.Header),
+	}
+}
+
+// Error method returns the HTTP status and response body.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, bytes.TrimSpace(e.Body))
+}
+
+// do method sends the request and decodes the JSON response into out,
+// if out is not nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return &Error{StatusCode: resp.StatusCode, Status: resp.Status, Body: b}
+	}
+
+	if out == nil {
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+	if err = json.NewDecoder(resp.Body).Decode(out); err == io.EOF {
+		return nil
+	}
+	return err
+}
+`))
+
+var goClientAPITmpl = template.Must(template.New("api").Funcs(goClientFuncMap).Parse(`// Code generated by aah CLI. DO NOT EDIT.
+
+package {{ .Package }}
+
+//aah:imports
+{{ range .Operations }}
+// {{ .Name }} method calls '{{ .Method }} {{ .Path }}' (route '{{ .RouteName }}'),
+// JSON response is decoded into out if it's not nil.
+{{- with comment .Doc }}
+//
+{{ . }}
+{{- end }}
+func (c *Client) {{ .Name }}(ctx context.Context, {{ range .Params }}{{ .VarName }} {{ goType .Schema }}, {{ end }}out interface{}) error {
+	{{- if .Query }}
+	q := url.Values{}
+	{{- range .Query }}
+	{{ goQueryStmt . }}
+	{{- end }}
+	{{- end }}
+	return c.do(ctx, "{{ .Method }}", {{ goPathExpr . }}, {{ if .Query }}q{{ else }}nil{{ end }}, {{ if .Body }}{{ .Body.VarName }}{{ else }}nil{{ end }}, out)
+}
+{{ end }}`))
+
+var goClientModelsTmpl = template.Must(template.New("models").Funcs(goClientFuncMap).Parse(`// Code generated by aah CLI. DO NOT EDIT.
+
+package {{ .Package }}
+
+//aah:imports
+{{ range .Models }}
+{{ with comment .Doc }}{{ . }}{{ else }}// {{ .Name }} model of API.{{ end }}
+{{ if .Schema -}}
+type {{ .Name }} {{ goType .Schema }}
+{{- else -}}
+type {{ .Name }} struct {
+	{{- range .Fields }}
+	{{- with comment .Doc }}
+	{{ . }}
+	{{- end }}
+	{{ .Name }} {{ goType .Schema }} ` + "`" + `json:"{{ .JSONName }}{{ if not .Required }},omitempty{{ end }}"` + "`" + `
+	{{- end }}
+}
+{{- end }}
+{{ end }}`))
+
+var goClientExampleTmpl = template.Must(template.New("example").Funcs(goClientFuncMap).Parse(`// Code generated by aah CLI. DO NOT EDIT.
+
+package {{ .Package }}
+
+//aah:imports
+
+func ExampleNewClient() {
+	client := NewClient("http://localhost:8080/")
+	client.Header.Set("Authorization", "Bearer <token>")
+	fmt.Println(client.BaseURL)
+	// Output: http://localhost:8080
+}
+{{ range .Operations }}
+func ExampleClient_{{ .Name }}() {
+	client := NewClient("http://localhost:8080")
+
+	var result interface{}
+	if err := client.{{ .Name }}(context.Background(), {{ range .Params }}{{ goZero .Schema }}, {{ end }}&result); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(result)
+}
+{{ end }}`))
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// TypeScript client
+//___________________________________
+
+// tsClientReserved are the TypeScript reserved words and identifiers used by
+// generated client method.
+var tsClientReserved = map[string]bool{"break": true, "case": true, "catch": true, "class": true,
+	"const": true, "continue": true, "debugger": true, "default": true, "delete": true, "do": true,
+	"else": true, "enum": true, "export": true, "extends": true, "false": true, "finally": true,
+	"for": true, "function": true, "if": true, "import": true, "in": true, "instanceof": true,
+	"new": true, "null": true, "return": true, "super": true, "switch": true, "this": true,
+	"throw": true, "true": true, "try": true, "typeof": true, "var": true, "void": true,
+	"while": true, "with": true, "let": true, "static": true, "yield": true, "await": true,
+	"query": true}
+
+// generateTSClient method generates the TypeScript client module.
+func generateTSClient(api *clientAPI) (map[string][]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := tsClientTmpl.Execute(buf, api); err != nil {
+		return nil, fmt.Errorf("client: %s", err)
+	}
+	return map[string][]byte{api.Package + ".ts": buf.Bytes()}, nil
+}
+
+// tsIdent method returns the TypeScript identifier of name e.g.
+// 'create_user' to 'createUser'.
+func tsIdent(name string, exported bool) string {
+	ident := camelIdent(name, exported)
+	if !exported {
+		ident = strings.Replace(ident, "ID", "Id", -1)
+	}
+	if tsClientReserved[ident] {
+		ident += "Param"
+	}
+	return ident
+}
+
+// tsType method returns the TypeScript type of OpenAPI schema.
+func tsType(s *apiSchema) string {
+	if s == nil {
+		return "unknown"
+	}
+	if !ess.IsStrEmpty(s.Ref) {
+		return tsIdent(s.RefName(), true)
+	}
+
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return tsType(s.Items) + "[]"
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "Record<string, " + tsType(s.AdditionalProperties) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// tsPathExpr method returns the TypeScript template literal of request
+// path, path parameters are escaped.
+func tsPathExpr(op *clientOp) string {
+	expr := op.Path
+	for _, p := range op.Params {
+		if p.In == "path" {
+			expr = strings.Replace(expr, "{"+p.Name+"}", "${encodeURIComponent(String("+p.VarName+"))}", -1)
+		}
+	}
+	return "`" + expr + "`"
+}
+
+// tsProp method returns the property name, quoted if it is not a valid
+// identifier.
+func tsProp(name string) string {
+	for i, r := range name {
+		if !(unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+// jsDoc method returns the text as JSDoc comment with given indent.
+func jsDoc(indent, text string) string {
+	text = strings.TrimSpace(text)
+	if ess.IsStrEmpty(text) {
+		return ""
+	}
+
+	lines := strings.Split(strings.Replace(text, "*/", "*\\/", -1), "\n")
+	if len(lines) == 1 {
+		return indent + "/** " + lines[0] + " */\n"
+	}
+
+	doc := indent + "/**\n"
+	for _, l := range lines {
+		doc += strings.TrimRight(indent+" * "+l, " ") + "\n"
+	}
+	return doc + indent + " */\n"
+}
+
+// tsParams method returns the method parameters of operation, path and
+// body parameters are positional and query parameters are grouped into
+// 'query' object.
+func tsParams(op *clientOp) string {
+	var params, fields []string
+	queryRequired := false
+	for _, p := range op.Params {
+		if p.In != "query" {
+			params = append(params, p.VarName+": "+tsType(p.Schema))
+			continue
+		}
+
+		opt := "?"
+		if p.Required {
+			opt, queryRequired = "", true
+		}
+		fields = append(fields, tsProp(p.Name)+opt+": "+tsType(p.Schema))
+	}
+
+	if len(fields) > 0 {
+		query := "query: { " + strings.Join(fields, "; ") + " }"
+		if !queryRequired {
+			query += " = {}"
+		}
+		params = append(params, query)
+	}
+	return strings.Join(params, ", ")
+}
+
+var tsClientFuncMap = template.FuncMap{
+	"tsType":     tsType,
+	"tsPathExpr": tsPathExpr,
+	"tsProp":     tsProp,
+	"jsDoc":      jsDoc,
+	"tsParams":   tsParams,
+	"tsMethod":   func(name string) string { return strings.ToLower(name[:1]) + name[1:] },
+}
+
+var tsClientTmpl = template.Must(template.New("ts").Funcs(tsClientFuncMap).Parse(`// Code generated by aah CLI. DO NOT EDIT.
+//
+// TypeScript client of '{{ .AppName }}' API.
+{{ range .Models }}
+{{ with jsDoc "" .Doc }}{{ . }}{{ end -}}
+{{ if .Schema -}}
+{{ if .Schema.AdditionalProperties -}}
+export interface {{ .Name }} {
+  [key: string]: {{ tsType .Schema.AdditionalProperties }};
+}
+{{- else -}}
+export type {{ .Name }} = {{ tsType .Schema }};
+{{- end }}
+{{- else -}}
+export interface {{ .Name }} {
+{{- range .Fields }}
+{{ jsDoc "  " .Doc }}  {{ tsProp .JSONName }}{{ if not .Required }}?{{ end }}: {{ tsType .Schema }};
+{{- end }}
+}
+{{- end }}
+{{ end }}
+export interface ClientOptions {
+  /** Base URL of API e.g. 'https://api.example.com', defaults to same origin. */
+  baseURL?: string;
+  /** Headers added into every request e.g. 'Authorization'. */
+  headers?: Record<string, string>;
+  /** Fetch implementation, defaults to global fetch. */
+  fetch?: typeof fetch;
+}
+
+/** APIError is thrown by client methods for non-2xx HTTP response. */
+export class APIError extends Error {
+  constructor(public status: number, public statusText: string, public body: string) {
+    super(` + "`${status} ${statusText}: ${body}`" + `);
+    this.name = "APIError";
+  }
+}
+
+/** Client is the HTTP client of '{{ .AppName }}' API. */
+export class Client {
+  baseURL: string;
+  headers: Record<string, string>;
+  private readonly fetchFn: typeof fetch;
+
+  constructor(options: ClientOptions = {}) {
+    this.baseURL = (options.baseURL || "").replace(/\/+$/, "");
+    this.headers = options.headers || {};
+    this.fetchFn = options.fetch || fetch.bind(globalThis);
+  }
+{{ range .Operations }}
+{{ jsDoc "  " (printf "%s %s (route '%s').\n\n%s" .Method .Path .RouteName .Doc) }}  {{ tsMethod .Name }}<T = unknown>({{ tsParams . }}): Promise<T> {
+    return this.request<T>("{{ .Method }}", {{ tsPathExpr . }}, {{ if .Query }}query{{ else }}undefined{{ end }}, {{ if .Body }}{{ .Body.VarName }}{{ else }}undefined{{ end }});
+  }
+{{ end }}
+  private async request<T>(method: string, path: string, query?: Record<string, unknown>, body?: unknown): Promise<T> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query || {})) {
+      if (value === undefined || value === null) {
+        continue;
+      }
+      for (const v of Array.isArray(value) ? value : [value]) {
+        params.append(key, v instanceof Date ? v.toISOString() : String(v));
+      }
+    }
+
+    const qs = params.toString();
+    const headers: Record<string, string> = { Accept: "application/json", ...this.headers };
+    if (body !== undefined) {
+      headers["Content-Type"] = "application/json; charset=utf-8";
+    }
+
+    const resp = await this.fetchFn(this.baseURL + path + (qs ? "?" + qs : ""), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+
+    const text = await resp.text();
+    if (!resp.ok) {
+      throw new APIError(resp.status, resp.statusText, text);
+    }
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+}
+`))
@@ -6,7 +6,6 @@ package main
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"go/format"
 	"io/ioutil"
@@ -14,7 +13,9 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"text/template"
 
 	"aahframework.org/aah.v0"
 	"aahframework.org/ainsp.v0"
@@ -29,6 +30,9 @@ type compileArgs struct {
 	ProjectCfg *config.Config
 	AppPack    bool
 	AppEmbed   bool
+	AppRace    bool
+	AppCover   bool
+	AppAssets  *assets
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -58,29 +62,15 @@ func compileApp(args *compileArgs) (string, error) {
 	registeredActions := aah.AppRouter().RegisteredActions()
 
 	// Go AST processing for Controllers
-	acntlr, errs := ainsp.Inspect(appControllersPath, ess.Excludes(excludes), registeredActions)
-	if len(acntlr.Packages) > 0 {
-		if len(errs) > 0 {
-			errMsgs := []string{}
-			for _, e := range errs {
-				errMsgs = append(errMsgs, e.Error())
-			}
-			return "", errors.New(strings.Join(errMsgs, "\n"))
-		}
+	acntlr, err := inspectActions(appControllersPath, ess.Excludes(excludes), registeredActions)
+	if err != nil {
+		return "", err
+	}
 
-		// Print router configuration missing/error details
-		missingActions := []string{}
-		for c, m := range acntlr.RegisteredActions {
-			for a, v := range m {
-				if v == 1 && !router.IsDefaultAction(a) {
-					missingActions = append(missingActions, fmt.Sprintf("%s.%s", c, a))
-				}
-			}
-		}
-		if len(missingActions) > 0 {
-			logError("Following actions are configured in 'routes.conf', however not implemented in Controller:\n\t",
-				strings.Join(missingActions, "\n\t"))
-		}
+	// Print router configuration missing/error details
+	if missingActions := unimplementedActions(acntlr); len(missingActions) > 0 {
+		logError("Following actions are configured in 'routes.conf', however not implemented in Controller:\n\t",
+			strings.Join(missingActions, "\n\t"))
 	}
 
 	appImportPaths := map[string]string{
@@ -98,29 +88,15 @@ func compileApp(args *compileArgs) (string, error) {
 
 	// Go AST processing for WebSockets
 	registeredWSActions := aah.AppRouter().RegisteredWSActions()
-	wsc, errs := ainsp.Inspect(appWebSocketsPath, ess.Excludes(excludes), registeredWSActions)
-	if len(wsc.Packages) > 0 {
-		if len(errs) > 0 {
-			errMsgs := []string{}
-			for _, e := range errs {
-				errMsgs = append(errMsgs, e.Error())
-			}
-			return "", errors.New(strings.Join(errMsgs, "\n"))
-		}
+	wsc, err := inspectActions(appWebSocketsPath, ess.Excludes(excludes), registeredWSActions)
+	if err != nil {
+		return "", err
+	}
 
-		// Print router configuration missing/error details
-		missingWSActions := []string{}
-		for c, m := range wsc.RegisteredActions {
-			for a, v := range m {
-				if v == 1 && !router.IsDefaultAction(a) {
-					missingWSActions = append(missingWSActions, fmt.Sprintf("%s.%s", c, a))
-				}
-			}
-		}
-		if len(missingWSActions) > 0 {
-			logError("Following WebSocket actions are configured in 'routes.conf', however not implemented in WebSocket:\n\t",
-				strings.Join(missingWSActions, "\n\t"))
-		}
+	// Print router configuration missing/error details
+	if missingWSActions := unimplementedActions(wsc); len(missingWSActions) > 0 {
+		logError("Following WebSocket actions are configured in 'routes.conf', however not implemented in WebSocket:\n\t",
+			strings.Join(missingWSActions, "\n\t"))
 	}
 
 	appWebSockets := wsc.FindTypeByEmbeddedType(fmt.Sprintf("%s.Context", libImportPath("ws")))
@@ -134,6 +110,20 @@ func compileApp(args *compileArgs) (string, error) {
 		appImportPaths[libImportPath("ainsp")] = "ainsp"
 	}
 
+	// validate view templates, broken view fails the build and warnings are
+	// logged
+	if projectCfg.BoolDefault("build.views.validate", true) {
+		diags := validateViews(appBaseDir, aah.AppConfig(), projectCfg)
+		for _, d := range diags {
+			if d.Severity == diagError {
+				return "", &diagnosticsError{Diagnostics: diags}
+			}
+		}
+		for _, d := range diags {
+			cliLog.Warn(d)
+		}
+	}
+
 	// prepare aah application version and build date
 	appVersion := getAppVersion(appBaseDir, projectCfg)
 	appBuildDate := getBuildDate()
@@ -153,6 +143,16 @@ func compileApp(args *compileArgs) (string, error) {
 		buildArgs = append(buildArgs, "-tags", tags)
 	}
 
+	if args.AppRace {
+		buildArgs = append(buildArgs, "-race")
+	}
+
+	// atomic mode is required to reset counters after writing the profile
+	if args.AppCover {
+		buildArgs = append(buildArgs, "-cover", "-covermode", "atomic",
+			"-coverpkg", path.Join(appImportPath, "app")+"/...")
+	}
+
 	appBinary := appBinaryFile(projectCfg, appBuildDir)
 	appBinaryName := filepath.Base(appBinary)
 	buildArgs = append(buildArgs, "-o", appBinary)
@@ -160,23 +160,39 @@ func compileApp(args *compileArgs) (string, error) {
 	// main.go location e.g. path/to/import/app
 	buildArgs = append(buildArgs, path.Join(appImportPath, "app"))
 
+	// asset manifest of asset pipeline, if processed
+	assetManifest := map[string]string{}
+	if args.AppAssets != nil {
+		assetManifest = args.AppAssets.Manifest
+	}
+
 	// clean previously auto generated files
 	cleanupAutoGenFiles(appBaseDir)
 
-	if err := generateSource(appCodeDir, "aah.go", aahMainTemplate, map[string]interface{}{
-		"AppTargetCmd":   args.Cmd,
-		"AppProxyPort":   args.ProxyPort,
-		"AahVersion":     aah.Version,
-		"AppImportPath":  appImportPath,
-		"AppVersion":     appVersion,
-		"AppBuildDate":   appBuildDate,
-		"AppBinaryName":  appBinaryName,
-		"AppControllers": appControllers,
-		"AppWebSockets":  appWebSockets,
-		"AppImportPaths": appImportPaths,
-		"AppSecurity":    appSecurity,
-		"AppIsPackaged":  args.AppPack,
-		"AppIsEmbedded":  args.AppEmbed,
+	mainTmpl, err := appMainTemplate(appBaseDir, projectCfg)
+	if err != nil {
+		return "", err
+	}
+
+	if err := generateSource(appCodeDir, "aah.go", mainTmpl, map[string]interface{}{
+		"AppTargetCmd":      args.Cmd,
+		"AppProxyPort":      args.ProxyPort,
+		"AahVersion":        aah.Version,
+		"AppImportPath":     appImportPath,
+		"AppVersion":        appVersion,
+		"AppBuildDate":      appBuildDate,
+		"AppBinaryName":     appBinaryName,
+		"AppControllers":    appControllers,
+		"AppWebSockets":     appWebSockets,
+		"AppImportPaths":    appImportPaths,
+		"AppSecurity":       appSecurity,
+		"AppIsPackaged":     args.AppPack,
+		"AppIsEmbedded":     args.AppEmbed,
+		"AppIsCovered":      args.AppCover,
+		"AppBaseDir":        appBaseDir,
+		"AppAssetsEnabled":  args.AppAssets != nil,
+		"AppAssetURLPrefix": assetsURLPrefix(projectCfg),
+		"AppAssetManifest":  assetManifest,
 	}); err != nil {
 		return "", err
 	}
@@ -188,6 +204,9 @@ func compileApp(args *compileArgs) (string, error) {
 
 	// execute aah applictaion build
 	if _, err := execCmd(gocmd, buildArgs, false); err != nil {
+		if diags := parseDiagnostics(diagOriginCompile, err.Error(), appBaseDir); len(diags) > 0 {
+			return "", &diagnosticsError{Diagnostics: diags}
+		}
 		return "", err
 	}
 
@@ -196,7 +215,7 @@ func compileApp(args *compileArgs) (string, error) {
 	return appBinary, nil
 }
 
-func generateSource(dir, filename, templateSource string, templateArgs map[string]interface{}) error {
+func generateSource(dir, filename string, tmpl *template.Template, templateArgs map[string]interface{}) error {
 	if !ess.IsFileExists(dir) {
 		if err := ess.MkDirAll(dir, 0644); err != nil {
 			return err
@@ -205,15 +224,19 @@ func generateSource(dir, filename, templateSource string, templateArgs map[strin
 
 	file := filepath.Join(dir, filename)
 	buf := &bytes.Buffer{}
-	err := renderTmpl(buf, templateSource, templateArgs)
+	err := tmpl.Execute(buf, templateArgs)
 	if err != nil {
-		return err
+		return &diagnosticsError{Diagnostics: []*diagnostic{
+			generateDiagnostic(diagRelPath(aah.AppBaseDir(), file), nil, err),
+		}}
 	}
 
 	b := buf.Bytes()
 	if strings.HasSuffix(filename, ".go") {
 		if b, err = format.Source(b); err != nil {
-			return fmt.Errorf("aah '%s' file format source error: %s", filename, err)
+			return &diagnosticsError{Diagnostics: []*diagnostic{
+				generateDiagnostic(diagRelPath(aah.AppBaseDir(), file), buf.Bytes(), err),
+			}}
 		}
 	}
 
@@ -223,8 +246,80 @@ func generateSource(dir, filename, templateSource string, templateArgs map[strin
 	return nil
 }
 
+// appMainTemplate method returns the template of 'aah.go'. Project can supply
+// its own main template via 'build.main_template' or override the named
+// extension blocks 'imports', 'pre-init', 'post-init' and 'pre-shutdown' of
+// default main template via 'build.main_blocks'. Paths are relative to
+// application base directory.
+func appMainTemplate(appBaseDir string, projectCfg *config.Config) (*template.Template, error) {
+	mainText := aahMainTemplate
+	if mainFile := projectCfg.StringDefault("build.main_template", ""); !ess.IsStrEmpty(mainFile) {
+		b, err := readProjectFile(appBaseDir, mainFile)
+		if err != nil {
+			return nil, fmt.Errorf("build.main_template: %s", err)
+		}
+		mainText = string(b)
+		cliLog.Infof("Using main template '%s'", mainFile)
+	}
+
+	tmpl, err := template.New("aah.go").Funcs(appTemplateFuncs).Parse(mainText)
+	if err != nil {
+		return nil, fmt.Errorf("build.main_template: %s", err)
+	}
+
+	if blocksFile := projectCfg.StringDefault("build.main_blocks", ""); !ess.IsStrEmpty(blocksFile) {
+		b, err := readProjectFile(appBaseDir, blocksFile)
+		if err != nil {
+			return nil, fmt.Errorf("build.main_blocks: %s", err)
+		}
+		if tmpl, err = tmpl.New(filepath.Base(blocksFile)).Parse(string(b)); err != nil {
+			return nil, fmt.Errorf("build.main_blocks: %s", err)
+		}
+		cliLog.Infof("Using main template blocks '%s'", blocksFile)
+	}
+
+	return tmpl.Lookup("aah.go"), nil
+}
+
+func readProjectFile(appBaseDir, file string) ([]byte, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(appBaseDir, file)
+	}
+	return ioutil.ReadFile(file)
+}
+
 var notExistRegex = regexp.MustCompile(`cannot find package "(.*)" in any of`)
 
+// inspectActions method does Go AST processing of given directory with
+// registered actions from 'routes.conf'.
+func inspectActions(dir string, excludes ess.Excludes, registeredActions map[string]map[string]uint8) (*ainsp.PrgInfo, error) {
+	prg, errs := ainsp.Inspect(dir, excludes, registeredActions)
+	if len(prg.Packages) > 0 && len(errs) > 0 {
+		return nil, &diagnosticsError{Diagnostics: diagnosticsFromErrors(diagOriginInspect, errs, aah.AppBaseDir())}
+	}
+	return prg, nil
+}
+
+// unimplementedActions method returns the actions configured in
+// 'routes.conf', however not implemented, in the format of
+// '<controller>.<action>'.
+func unimplementedActions(prg *ainsp.PrgInfo) []string {
+	var actions []string
+	if len(prg.Packages) == 0 {
+		return actions
+	}
+
+	for c, m := range prg.RegisteredActions {
+		for a, v := range m {
+			if v == 1 && !router.IsDefaultAction(a) {
+				actions = append(actions, fmt.Sprintf("%s.%s", c, a))
+			}
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
+
 // checkAndGetAppDeps method project dependencies is present otherwise
 // it tries to get it if any issues it will return error. It internally uses
 // go list command.
@@ -348,18 +443,24 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"path/filepath"
 	"fmt"
+	{{ if .AppAssetsEnabled }}"html/template"{{ end }}
 	"os"
 	"os/signal"
 	"reflect"
 	"regexp"
+	{{ if .AppIsCovered }}"runtime/coverage"{{ end }}
+	"strings"
 	"syscall"
 	{{ if .AppSecurity }}
 	"aahframework.org/security.v0/authc"
 	"aahframework.org/security.v0/authz"{{ end }}{{ range $k, $v := $.AppImportPaths }}
 	{{ $v }} "{{ $k }}"{{ end }}
+	{{ block "imports" . }}{{ end }}
 )
 
 var (
@@ -368,9 +469,52 @@ var (
 	list       = flag.String("list", "", "Prints the embedded file/directory path that matches the given regex pattern.")
 	profile    = flag.String("profile", "", "Environment profile name to activate. For e.g.: dev, qa, prod, etc.")
 	version    = flag.Bool("version", false, "Prints the aah application binary name, version and build timestamp.")
+	verifyVFS  = flag.Bool("verify-embedded", false, "Verifies the embedded files content against SHA-256 hash computed at build time.")
 	_          = reflect.Invalid
+	_          = strings.TrimSpace
+
+	// vfsFileHashes holds SHA-256 hash of embedded files, it gets populated
+	// by generated VFS files.
+	vfsFileHashes = make(map[string]string)
+{{ if .AppAssetsEnabled }}
+	// assetURLPrefix and assetManifest are from asset pipeline, manifest maps
+	// logical asset name to content-hashed name.
+	assetURLPrefix = "{{ .AppAssetURLPrefix }}"
+	assetManifest  = map[string]string{ {{ range $k, $v := .AppAssetManifest }}
+		{{ printf "%q" $k }}: {{ printf "%q" $v }},{{ end }}
+	}{{ end }}
 )
 
+// vfsPhysicalPath method resolves the mount physical path, relative path is
+// resolved against application base directory. For packaged application it
+// is parent directory of binary location.
+func vfsPhysicalPath(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	{{ if .AppIsPackaged }}
+	if exe, err := os.Executable(); err == nil {
+		if exe, err = filepath.EvalSymlinks(exe); err == nil {
+			return filepath.Join(filepath.Dir(filepath.Dir(exe)), filepath.FromSlash(p))
+		}
+	}
+	{{ end }}
+	return filepath.Join({{ printf "%q" .AppBaseDir }}, filepath.FromSlash(p))
+}
+
+{{ if .AppAssetsEnabled -}}
+// AssetPath method returns the URL path of given logical asset name, it
+// resolves the content-hashed name from asset manifest. It is available in
+// views as 'asset', e.g. {{"{{"}} asset "css/aah.css" {{"}}"}}.
+func AssetPath(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if hashed, found := assetManifest[name]; found {
+		name = hashed
+	}
+	return assetURLPrefix + "/" + name
+}
+{{- end }}
+
 func MergeSuppliedConfig(_ *aah.Event) {
 	cpath, err := filepath.Abs(*configPath)
 	if err != nil {
@@ -420,6 +564,32 @@ func PrintFilepath(pattern string) {
 	}
 }
 
+func VerifyEmbeddedFiles() int {
+	if !aah.AppVFS().IsEmbeddedMode() {
+		fmt.Println("'"+aah.AppBuildInfo().BinaryName + "' binary does not have embedded files.")
+		return 1
+	}
+
+	mismatch := 0
+	for fpath, hash := range vfsFileHashes {
+		b, err := aah.AppVFS().ReadFile(fpath)
+		if err != nil {
+			fmt.Printf("%-8s: %s (%s)\n", "ERROR", fpath, err)
+			mismatch++
+			continue
+		}
+
+		sum := sha256.Sum256(b)
+		if hex.EncodeToString(sum[:]) != hash {
+			fmt.Printf("%-8s: %s\n", "MISMATCH", fpath)
+			mismatch++
+		}
+	}
+
+	fmt.Printf("Verified %d embedded files, %d mismatch(es)\n", len(vfsFileHashes), mismatch)
+	return mismatch
+}
+
 {{ if eq .AppTargetCmd "RunCmd" -}}
 {{ if .AppProxyPort -}}
 func RunCmdSetAppProxyPort(e *aah.Event) {
@@ -428,6 +598,24 @@ func RunCmdSetAppProxyPort(e *aah.Event) {
 {{- end }}
 {{- end }}
 
+{{ if .AppIsCovered -}}
+// WriteCoverProfile method writes the coverage counters into GOCOVERDIR and
+// resets them, remaining counters are written by Go runtime on exit.
+func WriteCoverProfile() {
+	dir := os.Getenv("GOCOVERDIR")
+	if ess.IsStrEmpty(dir) {
+		return
+	}
+
+	if err := coverage.WriteCountersDir(dir); err != nil {
+		log.Errorf("Unable to write coverage profile: %s", err)
+		return
+	}
+	_ = coverage.ClearCounters()
+	log.Infof("Coverage profile written to %s", dir)
+}
+{{- end }}
+
 func main() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -462,6 +650,13 @@ func main() {
 		return
 	}
 
+	if *verifyVFS {
+		if VerifyEmbeddedFiles() > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Apply supplied external config file
 	if !ess.IsStrEmpty(*configPath) {
 		aah.OnInit(MergeSuppliedConfig)
@@ -472,8 +667,15 @@ func main() {
 		aah.OnInit(ActivateAppEnvProfile)
 	}
 
+	{{ block "pre-init" . }}{{ end }}
+
 	log.Infof("aah framework v%s, requires ≥ go1.8", aah.Version)
 
+	{{ if .AppAssetsEnabled -}}
+	// Asset pipeline view function
+	aah.AddTemplateFunc(template.FuncMap{"asset": AssetPath})
+	{{- end }}
+
 	if err := aah.Init("{{ .AppImportPath }}"); err != nil {
 		log.Fatal(err)
 	}
@@ -537,6 +739,8 @@ func main() {
 
 	aah.AppLog().Info("aah application initialized successfully")
 
+	{{ block "post-init" . }}{{ end }}
+
 	{{ if eq .AppTargetCmd "RunCmd" -}}
 	{{ if .AppProxyPort -}}
 	aah.OnStart(RunCmdSetAppProxyPort)
@@ -556,10 +760,16 @@ func main() {
 		aah.AppLog().Warn("Termination signal (SIGTERM) received")
 	}
 
+	{{ block "pre-shutdown" . }}{{ end }}
+
 	// Call aah shutdown
 	aah.Shutdown()
 	aah.AppLog().Info("aah application shutdown successful")
 
+	{{ if .AppIsCovered -}}
+	WriteCoverProfile()
+	{{- end }}
+
 	// bye bye, see you later.
 	os.Exit(0)
 }
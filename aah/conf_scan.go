@@ -0,0 +1,224 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// confKey holds the key path and its line position in the aah config file.
+// For section, EndLine is the line of closing brace.
+type confKey struct {
+	Path    string
+	Line    int
+	EndLine int
+	Section bool
+}
+
+// confKeys holds the scanned keys of aah config file in the order of
+// appearance.
+type confKeys []*confKey
+
+// Find method returns the key for given key path, nil if not found.
+func (ck confKeys) Find(keyPath string) *confKey {
+	for _, k := range ck {
+		if k.Path == keyPath {
+			return k
+		}
+	}
+	return nil
+}
+
+// Line method returns the line position of given key path, if key path is
+// not found then the line of nearest parent section, otherwise zero.
+func (ck confKeys) Line(keyPath string) int {
+	for p := keyPath; len(p) > 0; {
+		if k := ck.Find(p); k != nil {
+			return k.Line
+		}
+		idx := strings.LastIndex(p, ".")
+		if idx == -1 {
+			break
+		}
+		p = p[:idx]
+	}
+	return 0
+}
+
+// Leaves method returns the non-section keys.
+func (ck confKeys) Leaves() []*confKey {
+	var leaves []*confKey
+	for _, k := range ck {
+		if !k.Section {
+			leaves = append(leaves, k)
+		}
+	}
+	return leaves
+}
+
+type confToken struct {
+	Value  string
+	Line   int
+	Quoted bool
+}
+
+func (t confToken) is(v string) bool {
+	return !t.Quoted && t.Value == v
+}
+
+// scanConfFile method scans the aah config file (HOCON like syntax) for key
+// paths and its line positions. Config file syntax validation is done by
+// config library, scanner is lenient on it.
+func scanConfFile(file string) (confKeys, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return scanConf(string(b))
+}
+
+func scanConf(s string) (confKeys, error) {
+	tokens := tokenizeConf(s)
+
+	// stack of opened sections, dotted section key opens multiple sections
+	// at once and its closed together
+	var keys confKeys
+	var stack [][]*confKey
+	prefix := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		top := stack[len(stack)-1]
+		return top[len(top)-1].Path + "."
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.is("\n"), t.is(","):
+			continue
+		case t.is("}"):
+			if len(stack) == 0 {
+				return keys, fmt.Errorf("line %d: unexpected '}'", t.Line)
+			}
+			for _, k := range stack[len(stack)-1] {
+				k.EndLine = t.Line
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		case t.is("include"):
+			if i+1 < len(tokens) && tokens[i+1].Quoted {
+				i++
+				continue
+			}
+		}
+
+		// key, dotted key creates implicit sections
+		i++
+		for i < len(tokens) && tokens[i].is("\n") {
+			i++
+		}
+		if i >= len(tokens) {
+			return keys, fmt.Errorf("line %d: key '%s' without value", t.Line, t.Value)
+		}
+
+		isSection := tokens[i].is("{")
+		if tokens[i].is("=") || tokens[i].is(":") {
+			i++
+			isSection = i < len(tokens) && tokens[i].is("{")
+		}
+
+		segments := strings.Split(t.Value, ".")
+		if t.Quoted {
+			segments = []string{t.Value}
+		}
+
+		var group []*confKey
+		p := prefix()
+		for idx := range segments {
+			k := &confKey{
+				Path:    p + strings.Join(segments[:idx+1], "."),
+				Line:    t.Line,
+				Section: isSection || idx < len(segments)-1,
+			}
+			if k.Section && !isSection {
+				k.EndLine = t.Line
+			}
+			keys = append(keys, k)
+			group = append(group, k)
+		}
+
+		if isSection {
+			stack = append(stack, group)
+			continue
+		}
+
+		// skip value
+		depth := 0
+		for ; i < len(tokens); i++ {
+			v := tokens[i]
+			if v.is("[") || v.is("{") {
+				depth++
+			} else if v.is("]") || (v.is("}") && depth > 0) {
+				depth--
+			} else if depth == 0 && (v.is("\n") || v.is(",") || v.is("}")) {
+				break
+			}
+		}
+		if i < len(tokens) && tokens[i].is("}") {
+			i-- // section close is handled by main loop
+		}
+	}
+
+	if len(stack) > 0 {
+		k := stack[len(stack)-1][0]
+		return keys, fmt.Errorf("line %d: section '%s' is not closed", k.Line, k.Path)
+	}
+	return keys, nil
+}
+
+func tokenizeConf(s string) []confToken {
+	var tokens []confToken
+	line := 1
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\n':
+			tokens = append(tokens, confToken{Value: "\n", Line: line})
+			line++
+		case c == ' ' || c == '\t' || c == '\r':
+		case c == '#' || (c == '/' && i+1 < len(s) && s[i+1] == '/'):
+			for i+1 < len(s) && s[i+1] != '\n' {
+				i++
+			}
+		case strings.IndexByte("{}[]=:,", c) > -1:
+			tokens = append(tokens, confToken{Value: string(c), Line: line})
+		case c == '"':
+			start := i + 1
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				} else if s[i] == '\n' {
+					line++
+				}
+			}
+			end := i
+			if end > len(s) {
+				end = len(s)
+			}
+			tokens = append(tokens, confToken{Value: s[start:end], Line: line, Quoted: true})
+		default:
+			start := i
+			for i+1 < len(s) && strings.IndexByte(" \t\r\n{}[]=:,#\"", s[i+1]) == -1 &&
+				!(s[i+1] == '/' && i+2 < len(s) && s[i+2] == '/') {
+				i++
+			}
+			tokens = append(tokens, confToken{Value: s[start : i+1], Line: line})
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,453 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+type (
+	// scaffoldController holds the controller info of 'generate controller'.
+	scaffoldController struct {
+		Ref         string // routes.conf reference e.g. 'admin/UserController'
+		Package     string
+		Type        string
+		Resource    string
+		Path        string
+		RoutePrefix string
+		ViewDir     string
+		File        string
+		ImportDir   string
+		Actions     []*scaffoldAction
+	}
+
+	// scaffoldAction holds the action and its route info.
+	scaffoldAction struct {
+		Name      string
+		Method    string
+		Path      string
+		RouteName string
+		ID        bool
+		Body      string
+	}
+
+	// routeEntry is the route to be added into 'routes.conf'.
+	routeEntry struct {
+		Name   string
+		Path   string
+		Method string
+		Attrs  [][2]string
+	}
+)
+
+// scaffoldRESTActions is the conventional actions of resource controller.
+var scaffoldRESTActions = map[string]struct {
+	Method string
+	Suffix string
+	ID     bool
+}{
+	"index":   {"GET", "", false},
+	"new":     {"GET", "/new", false},
+	"create":  {"POST", "", false},
+	"show":    {"GET", "/:id", true},
+	"edit":    {"GET", "/:id/edit", true},
+	"update":  {"PUT", "/:id", true},
+	"delete":  {"DELETE", "/:id", true},
+	"destroy": {"DELETE", "/:id", true},
+}
+
+func generateControllerAction(c *cli.Context) error {
+	if c.NArg() == 0 {
+		_ = cli.ShowSubcommandHelp(c)
+		return nil
+	}
+
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+
+	actions := strings.Split(firstNonEmpty(c.String("a"), c.String("actions"), "index"), ",")
+	ctrl, err := newScaffoldController(c.Args().First(), actions)
+	if err != nil {
+		logFatal(err)
+	}
+
+	isWeb := ess.IsFileExists(filepath.Join(aah.AppBaseDir(), "views")) && !c.Bool("no-views")
+	ctrl.prepareBodies(isWeb)
+
+	// controller file
+	destFile := filepath.Join(aah.AppBaseDir(), "app", "controllers", filepath.FromSlash(ctrl.ImportDir), ctrl.File)
+	if checkAndConfirmOverwrite(c, destFile) {
+		return nil
+	}
+	if err = generateScaffoldSource(destFile, aahControllerTemplate, ctrl); err != nil {
+		logFatal(err)
+	}
+	cliLog.Infof("Generated controller '%s' at %s", ctrl.Ref, stripGoSrcPath(destFile))
+
+	// view stubs for web application
+	if isWeb {
+		ctrl.generateViews(aah.AppConfig())
+	}
+
+	// routes.conf wiring
+	routesFile := filepath.Join(aah.AppBaseDir(), "config", "routes.conf")
+	domain := firstNonEmpty(c.String("d"), c.String("domain"))
+	if err = addRoutes(routesFile, domain, ctrl.routeEntries()); err != nil {
+		logFatalf("Unable to add routes into 'config/routes.conf': %s", err)
+	}
+
+	verifyScaffold(projectCfg, "controllers", aah.AppRouter().RegisteredActions(), libImportPath("aah"), ctrl)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// newScaffoldController method creates the controller info from name e.g.
+// 'admin/User' and actions. Conventional actions 'index', 'new', 'create',
+// 'show', 'edit', 'update' and 'delete' (or 'destroy') are mapped to RESTful
+// routes, other actions to 'GET /<resource>/<action>'.
+func newScaffoldController(name string, actions []string) (*scaffoldController, error) {
+	name = strings.Trim(filepath.ToSlash(strings.TrimSpace(name)), "/")
+	pkgDir, base := path.Split(name)
+	base = strings.TrimSuffix(base, "Controller")
+	if ess.IsStrEmpty(base) {
+		return nil, fmt.Errorf("controller name '%s' is not valid", name)
+	}
+
+	typeName := camelIdent(base, true)
+	resource := snakeCase(typeName)
+	ctrl := &scaffoldController{
+		Package:   "controllers",
+		Type:      typeName + "Controller",
+		Resource:  resource,
+		ImportDir: strings.TrimSuffix(pkgDir, "/"),
+		File:      resource + ".go",
+	}
+	ctrl.Ref = path.Join(ctrl.ImportDir, ctrl.Type)
+	ctrl.Path = "/" + path.Join(ctrl.ImportDir, pluralize(resource))
+	ctrl.RoutePrefix = strings.Replace(path.Join(ctrl.ImportDir, resource), "/", "_", -1)
+	ctrl.ViewDir = path.Join(ctrl.ImportDir, strings.ToLower(typeName))
+	if !ess.IsStrEmpty(ctrl.ImportDir) {
+		ctrl.Package = goPackageName(path.Base(ctrl.ImportDir))
+	}
+
+	seen := make(map[string]bool)
+	for _, a := range actions {
+		a = strings.TrimSpace(a)
+		if ess.IsStrEmpty(a) || seen[strings.ToLower(a)] {
+			continue
+		}
+		seen[strings.ToLower(a)] = true
+
+		sa := &scaffoldAction{Name: camelIdent(a, true), Method: "GET"}
+		if rest, found := scaffoldRESTActions[strings.ToLower(a)]; found {
+			sa.Method, sa.Path, sa.ID = rest.Method, ctrl.Path+rest.Suffix, rest.ID
+		} else {
+			sa.Path = ctrl.Path + "/" + snakeCase(sa.Name)
+		}
+		sa.RouteName = ctrl.RoutePrefix + "_" + snakeCase(sa.Name)
+		ctrl.Actions = append(ctrl.Actions, sa)
+	}
+
+	if len(ctrl.Actions) == 0 {
+		return nil, fmt.Errorf("controller '%s' does not have any action", name)
+	}
+	return ctrl, nil
+}
+
+// prepareBodies method creates the action body, web application renders
+// view for GET action and redirects to index for others. API application
+// replies JSON.
+func (sc *scaffoldController) prepareBodies(isWeb bool) {
+	var indexRoute string
+	for _, a := range sc.Actions {
+		if a.Name == "Index" {
+			indexRoute = a.RouteName
+		}
+	}
+
+	for _, a := range sc.Actions {
+		data := fmt.Sprintf(`aah.Data{"action": %q}`, a.Name)
+		if a.ID {
+			data = fmt.Sprintf(`aah.Data{"action": %q, "id": id}`, a.Name)
+		}
+
+		switch {
+		case isWeb && a.Method == "GET":
+			a.Body = fmt.Sprintf("c.Reply().HTML(%s)", data)
+		case isWeb && !ess.IsStrEmpty(indexRoute):
+			a.Body = fmt.Sprintf("c.Reply().Redirect(c.RouteURL(%q))", indexRoute)
+		case a.Method == "POST":
+			a.Body = fmt.Sprintf("c.Reply().Created().JSON(%s)", data)
+		case a.Method == "DELETE":
+			a.Body = "c.Reply().NoContent()"
+		default:
+			a.Body = fmt.Sprintf("c.Reply().Ok().JSON(%s)", data)
+		}
+	}
+}
+
+// generateViews method creates the view stubs of GET actions in
+// 'views/pages/<controller>/<action>.html' as per aah view lookup in
+// lowercase, existing view is not touched.
+func (sc *scaffoldController) generateViews(appCfg *config.Config) {
+	delims := strings.Split(appCfg.StringDefault("view.delimiters", "{{.}}"), ".")
+	if len(delims) != 2 {
+		delims = []string{"{{", "}}"}
+	}
+	ext := appCfg.StringDefault("view.ext", ".html")
+
+	for _, a := range sc.Actions {
+		if a.Method != "GET" {
+			continue
+		}
+
+		viewFile := filepath.Join(aah.AppBaseDir(), "views", "pages", filepath.FromSlash(sc.ViewDir), strings.ToLower(a.Name)+ext)
+		if ess.IsFileExists(viewFile) {
+			cliLog.Infof("View %s already exists, skipped", stripGoSrcPath(viewFile))
+			continue
+		}
+
+		buf := &bytes.Buffer{}
+		if err := renderTmpl(buf, aahViewStubTemplate, map[string]interface{}{
+			"L": delims[0], "R": delims[1], "Controller": sc.Ref, "Action": a,
+		}); err != nil {
+			logFatal(err)
+		}
+		if err := ess.MkDirAll(filepath.Dir(viewFile), permRWXRXRX); err != nil {
+			logFatal(err)
+		}
+		if err := ioutil.WriteFile(viewFile, buf.Bytes(), permRWRWRW); err != nil {
+			logFatal(err)
+		}
+		cliLog.Infof("Generated view %s", stripGoSrcPath(viewFile))
+	}
+}
+
+func (sc *scaffoldController) routeEntries() []*routeEntry {
+	var entries []*routeEntry
+	for _, a := range sc.Actions {
+		entries = append(entries, &routeEntry{
+			Name:   a.RouteName,
+			Path:   a.Path,
+			Method: a.Method,
+			Attrs:  [][2]string{{"controller", sc.Ref}, {"action", a.Name}},
+		})
+	}
+	return entries
+}
+
+// verifyScaffold method runs the controller or websocket inspection same as
+// compile, to confirm the generated type and actions are picked up. Type is
+// looked up by embedded 'Context' of given library import path.
+func verifyScaffold(projectCfg *config.Config, dir string, registered map[string]map[string]uint8,
+	libPath string, sc *scaffoldController) {
+	registeredActions := make(map[string]map[string]uint8)
+	for ctrl, actions := range registered {
+		registeredActions[ctrl] = actions
+	}
+	actions := make(map[string]uint8)
+	for _, a := range sc.Actions {
+		actions[a.Name] = 1
+	}
+	registeredActions[sc.Ref] = actions
+
+	excludes, _ := projectCfg.StringList("build.ast_excludes")
+	prg, err := inspectActions(filepath.Join(aah.AppBaseDir(), "app", dir), ess.Excludes(excludes), registeredActions)
+	if err != nil {
+		logError(err)
+		cliLog.Warnf("Unable to confirm '%s', fix above error(s)", sc.Ref)
+		return
+	}
+
+	importPath := path.Join(aah.AppImportPath(), "app", dir, sc.ImportDir)
+	for _, t := range prg.FindTypeByEmbeddedType(fmt.Sprintf("%s.Context", libPath)) {
+		if t.Name == sc.Type && t.ImportPath == importPath {
+			cliLog.Infof("'%s' is picked up with %d action(s)", sc.Ref, len(t.Methods))
+			return
+		}
+	}
+	cliLog.Warnf("'%s' is not picked up by inspection, check 'build.ast_excludes' in aah.project", sc.Ref)
+}
+
+// generateScaffoldSource method renders the Go source template, formats and
+// writes it into file.
+func generateScaffoldSource(file, text string, data interface{}) error {
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, text, data); err != nil {
+		return err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return &diagnosticsError{Diagnostics: []*diagnostic{generateDiagnostic(filepath.Base(file), buf.Bytes(), err)}}
+	}
+
+	if err = ess.MkDirAll(filepath.Dir(file), permRWXRXRX); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, src, permRWRWRW)
+}
+
+// addRoutes method inserts the route entries into 'routes' section of domain
+// in 'routes.conf', existing content and formatting is kept as-is. Domain
+// defaults to first domain of the file. Route which already exists is
+// skipped.
+func addRoutes(routesFile, domain string, entries []*routeEntry) error {
+	b, err := ioutil.ReadFile(routesFile)
+	if err != nil {
+		return err
+	}
+	keys, err := scanConf(string(b))
+	if err != nil {
+		return err
+	}
+
+	if ess.IsStrEmpty(domain) {
+		for _, k := range keys {
+			if k.Section && strings.HasPrefix(k.Path, "domains.") && strings.Count(k.Path, ".") == 1 {
+				domain = strings.TrimPrefix(k.Path, "domains.")
+				break
+			}
+		}
+	}
+	routesKey := keys.Find("domains." + domain + ".routes")
+	if routesKey == nil || !routesKey.Section {
+		return fmt.Errorf("'routes' section not found for domain '%s'", domain)
+	}
+	if routesKey.EndLine <= routesKey.Line {
+		return fmt.Errorf("'routes' section of domain '%s' is not multi-line", domain)
+	}
+
+	lines := strings.Split(string(b), "\n")
+	closeLine := lines[routesKey.EndLine-1]
+	indent := closeLine[:len(closeLine)-len(strings.TrimLeft(closeLine, " \t"))]
+	unit := "  "
+	if strings.Contains(indent, "\t") {
+		unit = "\t"
+	}
+
+	// child indent from existing route, otherwise one level deeper
+	childIndent := indent + unit
+	for _, k := range keys {
+		if strings.HasPrefix(k.Path, routesKey.Path+".") && k.Line > routesKey.Line {
+			l := lines[k.Line-1]
+			childIndent = l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+			break
+		}
+	}
+
+	var added []string
+	for _, e := range entries {
+		if keys.Find(routesKey.Path+"."+e.Name) != nil {
+			cliLog.Warnf("Route '%s' already exists in domain '%s', skipped", e.Name, domain)
+			continue
+		}
+
+		added = append(added, "", childIndent+e.Name+" {")
+		attrs := [][2]string{{"path", e.Path}}
+		if e.Method != "GET" {
+			attrs = append(attrs, [2]string{"method", e.Method})
+		}
+		for _, a := range append(attrs, e.Attrs...) {
+			added = append(added, fmt.Sprintf("%s%s%s = %q", childIndent, unit, a[0], a[1]))
+		}
+		added = append(added, childIndent+"}")
+		cliLog.Infof("Route '%s' %s %s added into domain '%s'", e.Name, e.Method, e.Path, domain)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	// avoid double blank line, when section already ends with blank line
+	if idx := routesKey.EndLine - 2; idx >= 0 && ess.IsStrEmpty(lines[idx]) {
+		added = added[1:]
+	}
+
+	result := append([]string{}, lines[:routesKey.EndLine-1]...)
+	result = append(result, added...)
+	result = append(result, lines[routesKey.EndLine-1:]...)
+	return ioutil.WriteFile(routesFile, []byte(strings.Join(result, "\n")), permRWRWRW)
+}
+
+// snakeCase method converts the Go identifier into snake case e.g.
+// 'UserProfile' into 'user_profile'.
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pluralize method returns the plural of English noun for route path, it
+// covers the common suffix rules only.
+func pluralize(noun string) string {
+	switch {
+	case strings.HasSuffix(noun, "s"), strings.HasSuffix(noun, "x"),
+		strings.HasSuffix(noun, "ch"), strings.HasSuffix(noun, "sh"):
+		return noun + "es"
+	case strings.HasSuffix(noun, "y") && len(noun) > 1 && !strings.ContainsAny(noun[len(noun)-2:len(noun)-1], "aeiou"):
+		return noun[:len(noun)-1] + "ies"
+	}
+	return noun + "s"
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Controller Templates
+//___________________________________
+
+const aahControllerTemplate = `// Generated by 'aah generate controller', feel free to customize it.
+
+package {{ .Package }}
+
+import (
+	"aahframework.org/aah.v0"
+)
+
+// {{ .Type }} handles the '{{ .Path }}' routes.
+type {{ .Type }} struct {
+	*aah.Context
+}
+{{ range .Actions }}
+// {{ .Name }} method handles '{{ .Method }} {{ .Path }}'.
+func (c *{{ $.Type }}) {{ .Name }}({{ if .ID }}id string{{ end }}) {
+	{{ .Body }}
+}
+{{ end }}`
+
+const aahViewStubTemplate = `{{ .L }} define "title" {{ .R }}<title>{{ .Controller }}.{{ .Action.Name }}</title>{{ .L }} end {{ .R }}
+
+{{ .L }} define "body" {{ .R }}
+<h1>{{ .Controller }}.{{ .Action.Name }}</h1>
+<p>Route: {{ .Action.Method }} {{ .Action.Path }}</p>
+{{ .L }} end {{ .R }}
+`
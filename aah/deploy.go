@@ -0,0 +1,376 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/essentials.v0"
+)
+
+const (
+	deployReleasesDir    = "releases"
+	deployCurrentLink    = "current"
+	deployTimeLayout     = "20060102150405"
+	deployDefaultKeepCnt = 5
+)
+
+var deployCmd = cli.Command{
+	Name:    "deploy",
+	Aliases: []string{"d"},
+	Usage:   "Deploys aah application artifact into local release directory with atomic switch",
+	Description: `Deploys aah build artifact (created by 'aah build') into local release directory.
+
+	Artifact gets unpacked into '<to>/releases/<version>-<timestamp>' and then
+	'<to>/current' symlink is switched atomically to the new release. Optionally
+	health check commands are executed before the switch; non-zero exit code of
+	any health check command aborts the deployment. Environment variable
+	'AAH_RELEASE_DIR' is set for health check commands.
+
+	Examples of short and long flags:
+		aah deploy -a build/aahwebsite-381eaa8-linux-amd64.zip -t /srv/app
+		aah deploy --artifact aahwebsite.zip --to /srv/app --keep 3
+		aah deploy -a aahwebsite.zip -t /srv/app -c "./bin/aahwebsite -version"
+
+	To rollback to previous release:
+		aah deploy --to /srv/app --rollback`,
+	Action: deployAction,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "a, artifact",
+			Usage: "Path of aah application build artifact (zip)",
+		},
+		cli.StringFlag{
+			Name:  "t, to",
+			Usage: "Deploy base directory, releases and 'current' symlink are maintained here",
+		},
+		cli.StringFlag{
+			Name:  "r, release",
+			Usage: "Release version name; the default is inferred from artifact file name",
+		},
+		cli.IntFlag{
+			Name:  "k, keep",
+			Usage: "No. of releases to keep in the releases directory",
+			Value: deployDefaultKeepCnt,
+		},
+		cli.StringSliceFlag{
+			Name:  "c, health-check",
+			Usage: "Health check command to run within new release directory before switch, can be repeated",
+		},
+		cli.BoolFlag{
+			Name:  "rollback",
+			Usage: "Rollbacks 'current' symlink to previous release",
+		},
+	},
+}
+
+func deployAction(c *cli.Context) error {
+	cliLog = initCLILogger(nil)
+
+	deployDir := getNonEmptyAbsPath(c.String("t"), c.String("to"))
+	if ess.IsStrEmpty(deployDir) {
+		_ = cli.ShowCommandHelp(c, "deploy")
+		return nil
+	}
+
+	if isWindowsOS() {
+		logFatal("Deploy command is not supported on windows, it relies on symlink")
+	}
+
+	if c.Bool("rollback") {
+		if err := rollbackRelease(deployDir); err != nil {
+			logFatal(err)
+		}
+		return nil
+	}
+
+	artifact := getNonEmptyAbsPath(c.String("a"), c.String("artifact"))
+	if ess.IsStrEmpty(artifact) {
+		logFatal("Deploy requires build artifact, provide it via '--artifact'")
+	}
+	if !ess.IsFileExists(artifact) {
+		logFatalf("Given artifact '%s' does not exists", artifact)
+	}
+
+	releaseName := firstNonEmpty(c.String("r"), c.String("release"),
+		releaseVersionFromArtifact(artifact))
+	releaseName += "-" + time.Now().UTC().Format(deployTimeLayout)
+
+	releasesDir := filepath.Join(deployDir, deployReleasesDir)
+	releaseDir := filepath.Join(releasesDir, releaseName)
+	cliLog.Infof("Deploy starts for '%s' into '%s'", filepath.Base(artifact), releaseDir)
+
+	if err := ess.MkDirAll(releasesDir, permRWXRXRX); err != nil {
+		logFatal(err)
+	}
+
+	// release directory is created exclusively, so deploy never unpacks into
+	// existing release (e.g. two deploys within a second)
+	if err := os.Mkdir(releaseDir, permRWXRXRX); err != nil {
+		if os.IsExist(err) {
+			logFatalf("Release directory '%s' already exists, try again or provide different '--release'", releaseDir)
+		}
+		logFatal(err)
+	}
+
+	if err := unzipArchive(artifact, releaseDir); err != nil {
+		removeRelease(deployDir, releaseDir)
+		logFatalf("Unable to unpack artifact: %s", err)
+	}
+
+	if err := runHealthChecks(releaseDir, c.StringSlice("health-check")); err != nil {
+		removeRelease(deployDir, releaseDir)
+		logFatalf("Health check failed, deployment aborted: %s", err)
+	}
+
+	if err := switchCurrentRelease(deployDir, releaseDir); err != nil {
+		removeRelease(deployDir, releaseDir)
+		logFatal(err)
+	}
+
+	keep := c.Int("keep")
+	if keep <= 0 {
+		keep = deployDefaultKeepCnt
+	}
+	pruneReleases(deployDir, keep)
+
+	cliLog.Infof("Deploy successful, '%s' points to '%s'\n",
+		filepath.Join(deployDir, deployCurrentLink), releaseDir)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// releaseVersionFromArtifact method infers release version name from artifact
+// naming convention <appbinaryname>-<appversion>-<goos>-<goarch>.zip
+func releaseVersionFromArtifact(artifact string) string {
+	name := ess.StripExt(filepath.Base(artifact))
+	name = strings.TrimSuffix(name, "-"+strings.ToLower(getGOARCH()))
+	name = strings.TrimSuffix(name, "-"+strings.ToLower(getGOOS()))
+	return name
+}
+
+func runHealthChecks(releaseDir string, checks []string) error {
+	for _, check := range checks {
+		if ess.IsStrEmpty(check) {
+			continue
+		}
+
+		cliLog.Infof("|-- Running health check: %s", check)
+		cmd := exec.Command("sh", "-c", check) // #nosec
+		cmd.Dir = releaseDir
+		cmd.Env = append(os.Environ(), "AAH_RELEASE_DIR="+releaseDir)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("'%s'\n%s\n%s", check, string(output), err)
+		}
+	}
+	return nil
+}
+
+// switchCurrentRelease method creates temporary symlink and renames it to
+// 'current', rename is atomic on POSIX filesystems.
+func switchCurrentRelease(deployDir, releaseDir string) error {
+	currentLink := filepath.Join(deployDir, deployCurrentLink)
+	tmpLink := fmt.Sprintf("%s.%d", currentLink, time.Now().UnixNano())
+
+	target, err := filepath.Rel(deployDir, releaseDir)
+	if err != nil {
+		target = releaseDir
+	}
+
+	if err = os.Symlink(target, tmpLink); err != nil {
+		return fmt.Errorf("unable to create symlink: %s", err)
+	}
+
+	if err = os.Rename(tmpLink, currentLink); err != nil {
+		ess.DeleteFiles(tmpLink)
+		return fmt.Errorf("unable to switch current release: %s", err)
+	}
+	return nil
+}
+
+func rollbackRelease(deployDir string) error {
+	releases := releaseDirs(deployDir)
+	current := currentRelease(deployDir)
+	if ess.IsStrEmpty(current) {
+		return fmt.Errorf("'%s' does not have current release", deployDir)
+	}
+
+	idx := -1
+	for i, r := range releases {
+		if filepath.Base(r) == current {
+			idx = i
+			break
+		}
+	}
+
+	if idx <= 0 {
+		return fmt.Errorf("no previous release found to rollback from '%s'", current)
+	}
+
+	previous := releases[idx-1]
+	cliLog.Infof("Rolling back from '%s' to '%s'", current, filepath.Base(previous))
+	if err := switchCurrentRelease(deployDir, previous); err != nil {
+		return err
+	}
+
+	cliLog.Infof("Rollback successful, '%s' points to '%s'\n",
+		filepath.Join(deployDir, deployCurrentLink), previous)
+	return nil
+}
+
+// pruneReleases method removes older releases beyond keep count. Current
+// release is never removed.
+func pruneReleases(deployDir string, keep int) {
+	releases := releaseDirs(deployDir)
+	if len(releases) <= keep {
+		return
+	}
+
+	current := currentRelease(deployDir)
+	for _, r := range releases[:len(releases)-keep] {
+		if filepath.Base(r) == current {
+			continue
+		}
+		cliLog.Debugf("|-- Removing old release: %s", r)
+		removeRelease(deployDir, r)
+	}
+}
+
+// removeRelease method deletes the release directory unless 'current' points
+// to it.
+func removeRelease(deployDir, releaseDir string) {
+	if filepath.Base(releaseDir) == currentRelease(deployDir) {
+		cliLog.Warnf("Release '%s' is current release, not removed", releaseDir)
+		return
+	}
+	ess.DeleteFiles(releaseDir)
+}
+
+// releaseDirs method returns release directories ordered by its deploy
+// timestamp, oldest first.
+func releaseDirs(deployDir string) []string {
+	dirs, err := ess.DirsPath(filepath.Join(deployDir, deployReleasesDir), false)
+	if err != nil {
+		return []string{}
+	}
+
+	var releases []string
+	for _, d := range dirs {
+		if d == filepath.Join(deployDir, deployReleasesDir) {
+			continue
+		}
+		releases = append(releases, d)
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releaseTimestamp(releases[i]) < releaseTimestamp(releases[j])
+	})
+	return releases
+}
+
+func releaseTimestamp(releaseDir string) string {
+	name := filepath.Base(releaseDir)
+	if idx := strings.LastIndex(name, "-"); idx > -1 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+func currentRelease(deployDir string) string {
+	target, err := os.Readlink(filepath.Join(deployDir, deployCurrentLink))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(target)
+}
+
+// unzipArchive method extracts the zip archive into given destination
+// directory. If archive has single root directory (aah packaged build) then
+// its content gets extracted directly into destination.
+func unzipArchive(archiveFile, destDir string) error {
+	zr, err := zip.OpenReader(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(zr)
+
+	rootPrefix := zipRootPrefix(zr.File)
+	destDir = filepath.Clean(destDir)
+	for _, zf := range zr.File {
+		name := strings.TrimPrefix(filepath.ToSlash(zf.Name), rootPrefix)
+		if ess.IsStrEmpty(name) {
+			continue
+		}
+
+		dst := filepath.Join(destDir, filepath.FromSlash(name))
+		if dst != destDir && !strings.HasPrefix(dst, destDir+string(filepath.Separator)) {
+			return fmt.Errorf("illegal file path in archive: %s", zf.Name)
+		}
+
+		if zf.FileInfo().IsDir() {
+			if err = ess.MkDirAll(dst, permRWXRXRX); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = extractZipFile(zf, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractZipFile(zf *zip.File, dst string) error {
+	if err := ess.MkDirAll(filepath.Dir(dst), permRWXRXRX); err != nil {
+		return err
+	}
+
+	r, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(r)
+
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, zf.Mode())
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(f)
+
+	_, err = io.Copy(f, r) // #nosec
+	return err
+}
+
+func zipRootPrefix(files []*zip.File) string {
+	var root string
+	for _, zf := range files {
+		name := filepath.ToSlash(zf.Name)
+		idx := strings.Index(name, "/")
+		if idx == -1 {
+			return "" // file at archive root
+		}
+		if ess.IsStrEmpty(root) {
+			root = name[:idx+1]
+		} else if root != name[:idx+1] {
+			return ""
+		}
+	}
+	return root
+}
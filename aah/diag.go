@@ -0,0 +1,254 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/essentials.v0"
+)
+
+// Diagnostic origins, it tells which stage of the CLI reported the
+// diagnostic.
+const (
+	diagOriginInspect  = "inspect"
+	diagOriginGenerate = "generate"
+	diagOriginCompile  = "compile"
+	diagOriginCheck    = "check"
+	diagOriginView     = "view"
+	diagOriginI18n     = "i18n"
+)
+
+// Diagnostic severities.
+const (
+	diagError   = "error"
+	diagWarning = "warning"
+	diagNote    = "note"
+)
+
+// diagPosRegex matches the Go tool position prefix e.g.
+// 'path/to/file.go:12:5: message' and 'C:\path\file.go:12: message'.
+var diagPosRegex = regexp.MustCompile(`^((?:[A-Za-z]:)?[^:\s][^:]*?):(\d+)(?::(\d+))?: (.*)$`)
+
+// diagSrcPosRegex matches the position of generated source error e.g.
+// '12:5: message' from 'go/format' and 'template: aah.go:12:5: message'
+// from template execution.
+var diagSrcPosRegex = regexp.MustCompile(`^(?:template: [^:]+:)?(\d+)(?::(\d+))?: (?s)(.*)$`)
+
+var diagFormatFlag = cli.StringFlag{
+	Name:  "format",
+	Usage: "Diagnostics output format, 'text' or 'json' (one object per line)",
+	Value: "text",
+}
+
+// diagnostic is the structured error or warning reported by controller
+// inspection, view validation, code generation, compilation or check.
+type diagnostic struct {
+	Origin   string `json:"origin"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Excerpt  string `json:"excerpt,omitempty"`
+}
+
+// diagnosticsError is the error holds the structured diagnostics.
+type diagnosticsError struct {
+	Diagnostics []*diagnostic
+}
+
+func (d *diagnostic) String() string {
+	pos := d.File
+	if d.Line > 0 {
+		pos = fmt.Sprintf("%s:%d", pos, d.Line)
+		if d.Column > 0 {
+			pos = fmt.Sprintf("%s:%d", pos, d.Column)
+		}
+	}
+
+	s := fmt.Sprintf("%s: %s: %s", firstNonEmpty(pos, d.Origin), d.Severity, d.Message)
+	if !ess.IsStrEmpty(d.Excerpt) {
+		s += "\n\t" + strings.Replace(d.Excerpt, "\t", "    ", -1)
+		if d.Column > 0 {
+			prefix := d.Excerpt
+			if d.Column-1 < len(prefix) {
+				prefix = prefix[:d.Column-1]
+			}
+			s += "\n\t" + strings.Repeat(" ", len(strings.Replace(prefix, "\t", "    ", -1))) + "^"
+		}
+	}
+	return s
+}
+
+func (e *diagnosticsError) Error() string {
+	var lines []string
+	for _, d := range e.Diagnostics {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// parseDiagnostics method parses the Go tool output into diagnostics, file
+// path is made relative to baseDir and source excerpt is read from the file.
+// Tab indented lines are continuation of previous message. It returns nil if
+// output does not have any positioned message.
+func parseDiagnostics(origin, output, baseDir string) []*diagnostic {
+	var diags []*diagnostic
+	var last *diagnostic
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "\t") && last != nil {
+			last.Message += "\n" + strings.TrimSpace(line)
+			continue
+		}
+
+		m := diagPosRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			last = nil
+			continue
+		}
+
+		d := &diagnostic{Origin: origin, Severity: diagError, Message: m[4]}
+		d.Line, _ = strconv.Atoi(m[2])
+		d.Column, _ = strconv.Atoi(m[3])
+		for _, sev := range []string{diagWarning, diagNote} {
+			if strings.HasPrefix(d.Message, sev+": ") {
+				d.Severity, d.Message = sev, strings.TrimPrefix(d.Message, sev+": ")
+			}
+		}
+
+		file := m[1]
+		if !filepath.IsAbs(file) {
+			if wd, err := os.Getwd(); err == nil {
+				file = filepath.Join(wd, file)
+			}
+		}
+		d.Excerpt = sourceLine(file, d.Line)
+		d.File = diagRelPath(baseDir, file)
+
+		diags = append(diags, d)
+		last = d
+	}
+	return diags
+}
+
+// diagnosticsFromErrors method creates the diagnostics from errors, errors
+// with position (e.g. 'go/scanner' errors) are parsed.
+func diagnosticsFromErrors(origin string, errs []error, baseDir string) []*diagnostic {
+	var diags []*diagnostic
+	for _, err := range errs {
+		parsed := parseDiagnostics(origin, err.Error(), baseDir)
+		if len(parsed) == 0 {
+			parsed = append(parsed, &diagnostic{Origin: origin, Severity: diagError, Message: err.Error()})
+		}
+		diags = append(diags, parsed...)
+	}
+	return diags
+}
+
+// generateDiagnostic method creates the diagnostic for generated source
+// error, source excerpt is read from the generated source.
+func generateDiagnostic(filename string, src []byte, err error) *diagnostic {
+	d := &diagnostic{Origin: diagOriginGenerate, File: filename, Severity: diagError, Message: err.Error()}
+	if m := diagSrcPosRegex.FindStringSubmatch(err.Error()); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+		d.Column, _ = strconv.Atoi(m[2])
+		d.Message = m[3]
+		if lines := strings.Split(string(src), "\n"); d.Line > 0 && d.Line <= len(lines) {
+			d.Excerpt = strings.TrimRight(lines[d.Line-1], " \r")
+		}
+	}
+	return d
+}
+
+func sourceLine(file string, line int) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer ess.CloseQuietly(f)
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if n == line {
+			return strings.TrimRight(scanner.Text(), " \r")
+		}
+	}
+	return ""
+}
+
+func diagRelPath(baseDir, file string) string {
+	if !ess.IsStrEmpty(baseDir) {
+		if rel, err := filepath.Rel(baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+func sortDiagnostics(diags []*diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File < diags[j].File
+		}
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Column < diags[j].Column
+	})
+}
+
+// printDiagnostics method prints the diagnostics in given format 'text' or
+// 'json' to stdout. JSON format is one object per line, so it can be read
+// along with CLI log lines.
+func printDiagnostics(format string, diags []*diagnostic) {
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		for _, d := range diags {
+			_ = enc.Encode(d)
+		}
+		return
+	}
+
+	for _, d := range diags {
+		fmt.Println(d)
+	}
+}
+
+// logFatalDiag method logs the error and exits, diagnostics error is
+// rendered in the format of '--format' flag.
+func logFatalDiag(c *cli.Context, err error) {
+	format := strings.ToLower(c.String("format"))
+	de, ok := err.(*diagnosticsError)
+	if format != "json" {
+		if ok {
+			logError("Compile failed with following diagnostics:\n", de.Error())
+			exit(1)
+		}
+		logFatal(err)
+	}
+
+	if !ok {
+		de = &diagnosticsError{Diagnostics: []*diagnostic{{Severity: diagError, Message: err.Error()}}}
+	}
+	printDiagnostics(format, de.Diagnostics)
+	exit(1)
+}
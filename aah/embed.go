@@ -7,18 +7,29 @@ package main
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go/format"
 	"io"
 	"io/ioutil"
+	"mime"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
+	"unicode"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 
 	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
 	"aahframework.org/essentials.v0"
 	"aahframework.org/vfs.v0"
 )
@@ -27,62 +38,227 @@ import (
 // to Gzip by default. Read: https://en.wikipedia.org/wiki/Maximum_transmission_unit
 var defaultGzipMinSize int64 = 1400
 
+// vfsGenFormat is the format version of generated VFS source and blob, bump
+// it whenever generated output changes so the build cache gets invalidated.
+const vfsGenFormat = "4"
+
+// Supported compression codecs, names are HTTP content-coding values.
+const (
+	codecGzip   = "gzip"
+	codecBrotli = "br"
+	codecZstd   = "zstd"
+)
+
+// vfsCompress holds the compression configuration of mount point. File
+// content is stored gzipped (if applicable), it is served by aah static file
+// delivery as-is. Other codecs are stored as precompressed variants in VFS
+// index of the blob, currently those are not served by aah.
+type vfsCompress struct {
+	Codecs     []string
+	Level      int
+	MinSize    int64
+	NoCompress []string
+}
+
+func (c *vfsCompress) has(codec string) bool {
+	return ess.IsSliceContainsString(c.Codecs, codec)
+}
+
+func (c *vfsCompress) skip(fi os.FileInfo) bool {
+	return fi.Size() <= c.MinSize || noCompress(c.NoCompress, fi.Name())
+}
+
+func (c *vfsCompress) String() string {
+	return fmt.Sprintf("%v|%d|%d|%v", c.Codecs, c.Level, c.MinSize, c.NoCompress)
+}
+
+// vfsCompressConfig method reads the compression config from given key path,
+// values not configured are taken from defaults.
+//
+//	compress {
+//		codecs = ["gzip", "br", "zstd"]
+//		level = 6
+//		min_size = 1400
+//	}
+func vfsCompressConfig(cfg *config.Config, keyPath string, defaults *vfsCompress) (*vfsCompress, error) {
+	c := &vfsCompress{
+		Codecs:     defaults.Codecs,
+		Level:      cfg.IntDefault(keyPath+".level", defaults.Level),
+		MinSize:    cfg.Int64Default(keyPath+".min_size", defaults.MinSize),
+		NoCompress: defaults.NoCompress,
+	}
+
+	if codecs, found := cfg.StringList(keyPath + ".codecs"); found {
+		c.Codecs = []string{}
+		for _, codec := range codecs {
+			codec = strings.ToLower(strings.TrimSpace(codec))
+			switch codec {
+			case "brotli":
+				codec = codecBrotli
+			case "zstandard":
+				codec = codecZstd
+			}
+			if codec != codecGzip && codec != codecBrotli && codec != codecZstd {
+				return nil, fmt.Errorf("%s.codecs: unsupported codec '%s', supported codecs are 'gzip', 'br', 'zstd'", keyPath, codec)
+			}
+			if !c.has(codec) {
+				c.Codecs = append(c.Codecs, codec)
+			}
+		}
+
+		if c.has(codecBrotli) || c.has(codecZstd) {
+			cliLog.Warnf("%s.codecs: aah static file delivery serves 'gzip' encoding only, 'br' and 'zstd' "+
+				"variants are stored in VFS index (see 'aah vfs cat -e')", keyPath)
+		}
+	}
+
+	return c, nil
+}
+
 var vfsTmpl = template.Must(template.New("vfs").Funcs(vfsTmplFuncMap).Parse(vfsTmplStr))
 
-func processMount(mode bool, appBaseDir, vroot, proot string, skipList ess.Excludes, noGzipList []string) error {
+// vfsEntry holds the directory or file info of mount point.
+type vfsEntry struct {
+	Path      string
+	MountPath string
+	Info      os.FileInfo
+}
+
+// vfsFileResult holds the processed file info, data files are in the build
+// cache.
+type vfsFileResult struct {
+	Hash     string
+	DataFile string
+	Variants []*vfsVariant
+	err      error
+}
+
+// vfsVariant holds precompressed variant of file.
+type vfsVariant struct {
+	Encoding string
+	DataFile string
+	Offset   int64
+	End      int64
+}
+
+// processMount method generates VFS source and blob of the mount point and
+// returns the generated file paths. If the mount inputs have not changed since
+// last run, then generation is skipped. Overlays replaces the sub directory of
+// mount point with another physical directory, e.g. asset pipeline output.
+func processMount(mode bool, appBaseDir, vroot, proot string, skipList ess.Excludes, compress *vfsCompress, overlays map[string]string) ([]string, error) {
 	proot = filepath.ToSlash(proot)
 	if !ess.IsFileExists(proot) {
-		return &os.PathError{Op: "open", Path: proot, Err: os.ErrNotExist}
+		return nil, &os.PathError{Op: "open", Path: proot, Err: os.ErrNotExist}
 	}
 
 	if mode {
 		cliLog.Infof("|-- Processing mount: '%s' <== '%s'", vroot, proot)
 	}
-	b, err := generateVFSSource(mode, vroot, proot, skipList, noGzipList)
-	if err != nil {
-		return err
+
+	// destination files
+	slug := strings.Replace(vroot, "/", "_", -1)
+	filename := filepath.Join(appBaseDir, "app", fmt.Sprintf("aah%s_vfs.go", slug))
+	blobname := fmt.Sprintf("aah%s_vfs.bin", slug)
+	outputs := []string{filename}
+	if mode {
+		outputs = append(outputs, filepath.Join(appBaseDir, "app", blobname))
 	}
 
-	// destination file
-	filename := fmt.Sprintf("aah%s_vfs.go", strings.Replace(vroot, "/", "_", -1))
-	return ioutil.WriteFile(filepath.Join(appBaseDir, "app", filename), b, permRWXRXRX)
-}
+	var entries []*vfsEntry
+	if mode {
+		var err error
+		if entries, err = walkMount(vroot, proot, skipList, overlays); err != nil {
+			return nil, err
+		}
+	}
 
-// generateVFSSource method creates Virtual FileSystem (VFS) code
-// to add files and directories within binary for configured Mount points
-// on file aah.project.
-func generateVFSSource(mode bool, vroot, proot string, skipList ess.Excludes, noGzipList []string) ([]byte, error) {
-	err := skipList.Validate()
+	cacheDir := filepath.Join(appBaseDir, "build", buildCacheDirName, "vfs")
+	sumFile := filepath.Join(cacheDir, "mount"+slug+".sum")
+	checksum := mountChecksum(mode, vroot, proot, skipList, compress, overlays, entries)
+	if isMountUnchanged(sumFile, checksum, outputs) {
+		cliLog.Infof("     |-- Unchanged, skipping regeneration")
+		return outputs, nil
+	}
+
+	var blob *os.File
+	if mode {
+		var err error
+		if blob, err = os.Create(outputs[1]); err != nil {
+			return nil, err
+		}
+		defer ess.CloseQuietly(blob)
+	}
+
+	b, err := generateVFSSource(mode, vroot, portablePath(appBaseDir, proot), entries, &vfsBlob{
+		Name:     blobname,
+		Var:      vfsBlobVarName(slug),
+		CacheDir: cacheDir,
+		w:        blob,
+		index: &vfsIndex{
+			MountPath:    vroot,
+			PhysicalPath: portablePath(appBaseDir, proot),
+			Excludes:     skipList,
+			Overlays:     portableOverlays(appBaseDir, proot, overlays),
+		},
+	}, compress)
 	if err != nil {
 		return nil, err
 	}
 
-	buf := &bytes.Buffer{}
-	startTmpl := "vfs_start_embed"
-	if !mode {
-		startTmpl = "vfs_start_mount"
-	}
-	if err = vfsTmpl.ExecuteTemplate(buf, startTmpl, aah.Data{
-		"Mode":         mode,
-		"MountPath":    vroot,
-		"PhysicalPath": proot,
-	}); err != nil {
+	if err = ioutil.WriteFile(filename, b, permRWXRXRX); err != nil {
 		return nil, err
 	}
 
-	// non-single binary mode, exit here
-	if !mode {
-		_s(fmt.Fprint(buf, "\n}"))
-		return format.Source(buf.Bytes())
+	_ = ess.MkDirAll(cacheDir, permRWXRXRX)
+	if err = ioutil.WriteFile(sumFile, []byte(checksum), permRWRWRW); err != nil {
+		cliLog.Debugf("Unable to write mount checksum: %s", err)
 	}
 
-	files := make(map[string]os.FileInfo)
-	if err := ess.Walk(proot, func(fpath string, info os.FileInfo, err error) error {
+	return outputs, nil
+}
+
+// vfsBlob holds the packed binary data of mount files. Generated VFS source
+// embeds it via 'go:embed' and refers to the file content by offsets.
+type vfsBlob struct {
+	Name     string
+	Var      string
+	CacheDir string
+	w        io.Writer
+	size     int64
+	index    *vfsIndex
+}
+
+func (b *vfsBlob) Write(p []byte) (int, error) {
+	n, err := b.w.Write(p)
+	b.size += int64(n)
+	return n, err
+}
+
+// walkMount method collects the directories and files of mount point except
+// skip list. Overlay directory content is collected in place of its
+// directory.
+func walkMount(vroot, proot string, skipList ess.Excludes, overlays map[string]string) ([]*vfsEntry, error) {
+	if err := skipList.Validate(); err != nil {
+		return nil, err
+	}
+
+	var entries []*vfsEntry
+	err := ess.Walk(proot, func(fpath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
 		fpath = filepath.ToSlash(fpath)
+		if odir, found := overlays[fpath]; found && info.IsDir() {
+			oentries, err := walkMount(path.Join(vroot, strings.TrimPrefix(fpath, proot)),
+				filepath.ToSlash(odir), skipList, nil)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, oentries...)
+			return filepath.SkipDir
+		}
+
 		fname := path.Base(fpath)
 		if skipList.Match(fname) {
 			if fname == "app" && strings.Contains(fpath, "/pages/") {
@@ -97,108 +273,429 @@ func generateVFSSource(mode bool, vroot, proot string, skipList ess.Excludes, no
 		}
 	sc:
 
-		if info.IsDir() {
-			mp := filepath.ToSlash(filepath.Join(vroot, strings.TrimPrefix(fpath, proot)))
+		if !info.IsDir() && isVFSGenFile(fname) {
+			return nil
+		}
+
+		entries = append(entries, &vfsEntry{
+			Path:      fpath,
+			MountPath: filepath.ToSlash(filepath.Join(vroot, strings.TrimPrefix(fpath, proot))),
+			Info:      info,
+		})
+		return nil
+	})
 
-			if err = vfsTmpl.ExecuteTemplate(buf, "vfs_dir", aah.Data{
-				"Node": &vfs.NodeInfo{Dir: info.IsDir(), Path: mp, Time: info.ModTime()},
-			}); err != nil {
-				return err
-			}
+	return entries, err
+}
+
+// mountChecksum method computes checksum of mount inputs. Directory
+// modification time is not considered since generated files are written into
+// application directory.
+func mountChecksum(mode bool, vroot, proot string, skipList ess.Excludes, compress *vfsCompress, overlays map[string]string, entries []*vfsEntry) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s|%s|%t|%s|%s|%v|%s|%v\n", Version, vfsGenFormat, mode, vroot, proot, skipList, compress, overlays)
+	for _, e := range entries {
+		if e.Info.IsDir() {
+			_, _ = fmt.Fprintf(h, "d|%s\n", e.Path)
 		} else {
-			files[fpath] = info
+			_, _ = fmt.Fprintf(h, "f|%s|%d|%d\n", e.Path, e.Info.Size(), e.Info.ModTime().UnixNano())
 		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
 
-		return nil
+func isMountUnchanged(sumFile, checksum string, outputs []string) bool {
+	for _, f := range outputs {
+		if !ess.IsFileExists(f) {
+			return false
+		}
+	}
+
+	b, err := ioutil.ReadFile(sumFile)
+	return err == nil && string(b) == checksum
+}
+
+// generateVFSSource method creates Virtual FileSystem (VFS) code
+// to add files and directories within binary for configured Mount points
+// on file aah.project. File content gets written into given blob. Physical
+// path is relative to application base directory if it is within.
+func generateVFSSource(mode bool, vroot, proot string, entries []*vfsEntry, blob *vfsBlob, compress *vfsCompress) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	startTmpl := "vfs_start_embed"
+	if !mode {
+		startTmpl = "vfs_start_mount"
+	}
+	if err := vfsTmpl.ExecuteTemplate(buf, startTmpl, aah.Data{
+		"Mode":         mode,
+		"MountPath":    vroot,
+		"PhysicalPath": proot,
+		"Blob":         blob,
 	}); err != nil {
 		return nil, err
 	}
 
-	_s(fmt.Fprintf(buf, "\n// Adding files into VFS\n"))
-	for fname, info := range files {
-		f, err := os.Open(fname)
-		if err != nil {
-			logError(err)
+	// non-single binary mode, exit here
+	if !mode {
+		_s(fmt.Fprint(buf, "\n}"))
+		return format.Source(buf.Bytes())
+	}
+
+	var files []*vfsEntry
+	for _, e := range entries {
+		if !e.Info.IsDir() {
+			files = append(files, e)
 			continue
 		}
 
-		cliLog.Debugf("     |-- Processing: %s", fname)
-		mp := filepath.ToSlash(filepath.Join(vroot, strings.TrimPrefix(fname, proot)))
-
-		if err = vfsTmpl.ExecuteTemplate(buf, "vfs_file", aah.Data{
-			"Node": &vfs.NodeInfo{DataSize: info.Size(), Path: mp, Time: info.ModTime()},
+		if err := vfsTmpl.ExecuteTemplate(buf, "vfs_dir", aah.Data{
+			"Node": &vfs.NodeInfo{Dir: true, Path: e.MountPath, Time: e.Info.ModTime()},
 		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// sorted order, it keeps the blob reproducible
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	results, err := convertFiles(files, blob.CacheDir, compress)
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	index := blob.index
+	for _, e := range entries {
+		if e.Info.IsDir() {
+			index.Entries = append(index.Entries, &vfsIndexEntry{
+				Path: e.MountPath,
+				Dir:  true,
+				Time: e.Info.ModTime().UTC(),
+			})
+		}
+	}
+
+	_s(fmt.Fprintf(buf, "\n// Adding files into VFS\n"))
+	for idx, e := range files {
+		offset := blob.size
+		if err = copyDataFile(blob, results[idx].DataFile); err != nil {
 			logError(err)
 			return nil, err
 		}
+		end := blob.size
 
-		if info.Size() > 0 {
-			if err = convertFile(buf, f, info, noGzip(noGzipList, info.Name())); err != nil {
+		for _, v := range results[idx].Variants {
+			v.Offset = blob.size
+			if err = copyDataFile(blob, v.DataFile); err != nil {
 				logError(err)
 				return nil, err
 			}
+			v.End = blob.size
+		}
+
+		ie := &vfsIndexEntry{
+			Path:   e.MountPath,
+			Size:   e.Info.Size(),
+			Time:   e.Info.ModTime().UTC(),
+			Offset: offset,
+			Length: end - offset,
+			Hash:   results[idx].Hash,
+		}
+		if ie.Length < ie.Size { // stored gzipped only if its smaller
+			ie.Encoding = codecGzip
+		}
+		for _, v := range results[idx].Variants {
+			ie.Variants = append(ie.Variants, &vfsIndexVariant{
+				Encoding: v.Encoding,
+				Offset:   v.Offset,
+				Length:   v.End - v.Offset,
+			})
+		}
+		index.Entries = append(index.Entries, ie)
+
+		if err = vfsTmpl.ExecuteTemplate(buf, "vfs_file", aah.Data{
+			"Node":   &vfs.NodeInfo{DataSize: e.Info.Size(), Path: e.MountPath, Time: e.Info.ModTime()},
+			"Blob":   blob,
+			"Offset": offset,
+			"End":    end,
+			"Hash":   results[idx].Hash,
+		}); err != nil {
+			logError(err)
+			return nil, err
 		}
-		_s(fmt.Fprint(buf, "\"))\n\n"))
-		ess.CloseQuietly(f)
+	}
+
+	index.DataSize = blob.size
+	if err = writeVFSIndex(blob, index); err != nil {
+		logError(err)
+		return nil, err
 	}
 
 	_s(fmt.Fprint(buf, "}"))
 	return format.Source(buf.Bytes())
 }
 
-func convertFile(buf *bytes.Buffer, r io.ReadSeeker, fi os.FileInfo, noGzip bool) error {
-	restorePoint := buf.Len()
-	w := &stringWriter{w: buf}
+// convertFiles method processes the files concurrently using worker pool,
+// results are in the same order of given files.
+func convertFiles(files []*vfsEntry, cacheDir string, compress *vfsCompress) ([]*vfsFileResult, error) {
+	results := make([]*vfsFileResult, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = convertCachedFile(files[idx], cacheDir, compress)
+			}
+		}()
+	}
+
+	for idx := range files {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, r := range results {
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+	return results, nil
+}
+
+// convertCachedFile method converts the file and its precompressed variants
+// into build cache, cache entry is keyed by path, size, modification time,
+// content hash and compression config. Existing cache entry gets reused.
+func convertCachedFile(e *vfsEntry, cacheDir string, compress *vfsCompress) *vfsFileResult {
+	f, err := os.Open(e.Path)
+	if err != nil {
+		return &vfsFileResult{err: err}
+	}
+	defer ess.CloseQuietly(f)
+
+	hash, err := contentHash(f)
+	if err != nil {
+		return &vfsFileResult{err: err}
+	}
+
+	result := &vfsFileResult{Hash: hash}
+	if e.Info.Size() == 0 {
+		return result
+	}
+
+	skip := compress.skip(e.Info)
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%s|%t|%s", e.Path, e.Info.Size(),
+		e.Info.ModTime().UnixNano(), hash, skip, compress)))
+	keyHex := hex.EncodeToString(key[:])
+	dataFile := filepath.Join(cacheDir, "files", keyHex[:2], keyHex)
+
+	// primary content, gzipped if applicable
+	result.DataFile = dataFile
+	if err = cacheDataFile(dataFile, func(w io.Writer) error {
+		_, err := convertFile(w, f, e.Info, skip || !compress.has(codecGzip), compress.Level)
+		return err
+	}); err != nil {
+		return &vfsFileResult{err: err}
+	}
+
+	if skip {
+		return result
+	}
+
+	// precompressed variants, stored only if its smaller than original
+	for _, codec := range compress.Codecs {
+		if codec == codecGzip {
+			continue
+		}
+
+		variantFile := dataFile + "." + codec
+		if err = cacheDataFile(variantFile, func(w io.Writer) error {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			buf, err := compressData(f, codec, compress.Level)
+			if err != nil || int64(buf.Len()) >= e.Info.Size() {
+				return err // empty cache file marks variant as not applicable
+			}
+			_, err = io.Copy(w, buf)
+			return err
+		}); err != nil {
+			return &vfsFileResult{err: err}
+		}
+
+		if fi, err := os.Stat(variantFile); err == nil && fi.Size() > 0 {
+			result.Variants = append(result.Variants, &vfsVariant{Encoding: codec, DataFile: variantFile})
+		}
+	}
+
+	return result
+}
+
+// cacheDataFile method creates the cache data file using given func if its
+// not exists already.
+func cacheDataFile(dataFile string, fn func(w io.Writer) error) error {
+	if ess.IsFileExists(dataFile) {
+		cliLog.Tracef("     |-- Cached: %s", dataFile)
+		return nil
+	}
 
-	// if its already less then MTU size or gzip not required
-	if fi.Size() <= defaultGzipMinSize || noGzip {
-		_, err := io.Copy(w, r)
+	if err := ess.MkDirAll(filepath.Dir(dataFile), permRWXRXRX); err != nil {
 		return err
 	}
 
-	gw := gzip.NewWriter(w)
-	_, err := io.Copy(gw, r)
+	tmpFile := dataFile + ".tmp"
+	df, err := os.Create(tmpFile)
 	if err != nil {
 		return err
 	}
 
-	if err = gw.Close(); err != nil {
+	err = fn(df)
+	ess.CloseQuietly(df)
+	if err == nil {
+		err = os.Rename(tmpFile, dataFile)
+	}
+	if err != nil {
+		ess.DeleteFiles(tmpFile)
+	}
+	return err
+}
+
+func copyDataFile(w io.Writer, dataFile string) error {
+	if ess.IsStrEmpty(dataFile) {
+		return nil
+	}
+
+	f, err := os.Open(dataFile)
+	if err != nil {
 		return err
 	}
+	defer ess.CloseQuietly(f)
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// convertFile method writes the file content into given writer, gzipped if
+// applicable. It returns the no. of bytes written.
+func convertFile(w io.Writer, r io.ReadSeeker, fi os.FileInfo, noGzip bool, level int) (int64, error) {
+	// gzip not required or applicable
+	if noGzip {
+		return io.Copy(w, r)
+	}
+
+	buf, err := compressData(r, codecGzip, level)
+	if err != nil {
+		return 0, err
+	}
 
-	if int64(w.size) >= fi.Size() {
+	if int64(buf.Len()) >= fi.Size() {
 		if _, err = r.Seek(0, io.SeekStart); err != nil {
-			return err
+			return 0, err
 		}
+		return io.Copy(w, r)
+	}
 
-		buf.Truncate(restorePoint)
-		if _, err = io.Copy(w, r); err != nil {
-			return err
+	return io.Copy(w, buf)
+}
+
+// compressData method compresses the given content with codec and level,
+// level value zero or less means codec default level.
+func compressData(r io.Reader, codec string, level int) (*bytes.Buffer, error) {
+	buf := &bytes.Buffer{}
+
+	var cw io.WriteCloser
+	var err error
+	switch codec {
+	case codecGzip:
+		if level <= 0 || level > gzip.BestCompression {
+			level = gzip.DefaultCompression
+		}
+		cw, err = gzip.NewWriterLevel(buf, level)
+	case codecBrotli:
+		if level <= 0 || level > brotli.BestCompression {
+			level = brotli.DefaultCompression
 		}
+		cw = brotli.NewWriterLevel(buf, level)
+	case codecZstd:
+		var opts []zstd.EOption
+		if level > 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		cw, err = zstd.NewWriter(buf, opts...)
+	default:
+		err = fmt.Errorf("vfs: unsupported compression codec '%s'", codec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = io.Copy(cw, r); err != nil {
+		return nil, err
 	}
 
-	return nil
+	if err = cw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
 }
 
-const lowerHex = "0123456789abcdef"
+// portablePath method returns the slash separated path relative to
+// application base directory if given path is within it, otherwise as-is.
+func portablePath(appBaseDir, p string) string {
+	rel, err := filepath.Rel(appBaseDir, filepath.FromSlash(p))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return p
+	}
+	return filepath.ToSlash(rel)
+}
 
-// https://github.com/go-bindata/go-bindata/blob/master/stringwriter.go
-type stringWriter struct {
-	w    io.Writer
-	size int
+// portableOverlays method returns the overlays with directory relative to
+// mount physical path and its replacement relative to application base
+// directory, so that build machine paths are not written into VFS index.
+func portableOverlays(appBaseDir, proot string, overlays map[string]string) map[string]string {
+	if len(overlays) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(overlays))
+	for k, v := range overlays {
+		result[portablePath(proot, k)] = portablePath(appBaseDir, v)
+	}
+	return result
 }
 
-func (s *stringWriter) Write(p []byte) (n int, err error) {
-	buf := []byte(`\x00`)
-	for _, b := range p {
-		buf[2], buf[3] = lowerHex[b/16], lowerHex[b%16]
-		if _, err = s.w.Write(buf); err != nil {
-			return
+func isVFSGenFile(name string) bool {
+	for _, pattern := range []string{"aah_*_vfs.go", "aah_*_vfs.bin"} {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
 		}
-		n++
-		s.size++
 	}
-	return
+	return false
+}
+
+func vfsBlobVarName(slug string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, slug)
+	return "vfsBlob" + toLowerCamelCase(strings.TrimRight(name, "_"))
+}
+
+// contentHash method returns SHA-256 hex value of given content and rewinds
+// the reader to the beginning.
+func contentHash(r io.ReadSeeker) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 func timeStr(t time.Time) string {
@@ -212,9 +709,33 @@ func timeStr(t time.Time) string {
 
 func _s(_ ...interface{}) {}
 
-func noGzip(noGzipList []string, name string) bool {
-	for _, t := range noGzipList {
-		if strings.HasSuffix(name, t) {
+// noCompress method reports whether file is in the no compress list. List
+// entry is either file suffix (e.g. '.png') or MIME type (e.g. 'image/*',
+// 'application/pdf').
+func noCompress(noCompressList []string, name string) bool {
+	var mimeType string
+	for _, t := range noCompressList {
+		if !strings.Contains(t, "/") {
+			if strings.HasSuffix(name, t) {
+				return true
+			}
+			continue
+		}
+
+		if ess.IsStrEmpty(mimeType) {
+			if mimeType = mime.TypeByExtension(filepath.Ext(name)); ess.IsStrEmpty(mimeType) {
+				continue
+			}
+			if idx := strings.IndexByte(mimeType, ';'); idx > 0 {
+				mimeType = strings.TrimSpace(mimeType[:idx])
+			}
+		}
+
+		if strings.HasSuffix(t, "/*") {
+			if strings.HasPrefix(mimeType, t[:len(t)-1]) {
+				return true
+			}
+		} else if strings.EqualFold(mimeType, t) {
 			return true
 		}
 	}
@@ -230,17 +751,23 @@ const vfsTmplStr = `{{ define "vfs_start_mount"}} // Code generated by aah CLI -
 package main
 
 import ({{ if .Mode }}
+  _ "embed"
   "time"{{ end }}
 
   "aahframework.org/aah.v0"
   "aahframework.org/log.v0"{{ if .Mode }}
 	"aahframework.org/vfs.v0"{{ end }}
 )
-
+{{ if .Mode }}
+// {{ .Blob.Var }} holds the mount file content, go:embed requires go1.16
+// or above.
+//go:embed {{ .Blob.Name }}
+var {{ .Blob.Var }} []byte
+{{ end }}
 func init() {
 	{{ if .Mode }}aah.AppVFS().SetEmbeddedMode(){{ end }}
 
-	if err := aah.AppVFS().AddMount("{{ .MountPath }}", "{{ .PhysicalPath }}"); err != nil {
+	if err := aah.AppVFS().AddMount("{{ .MountPath }}", vfsPhysicalPath("{{ .PhysicalPath }}")); err != nil {
 		log.Fatal("vfs: ", err)
 	}
 {{ end }}
@@ -270,6 +797,7 @@ func init() {
 		Path: "{{ .Node.Path }}",
 		Time: {{ .Node.Time | timestr }},
 	},
-	[]byte("
-{{- end }}
+	{{ if eq .Offset .End }}[]byte{}{{ else }}{{ .Blob.Var }}[{{ .Offset }}:{{ .End }}:{{ .End }}]{{ end }})
+	vfsFileHashes["{{ .Node.Path }}"] = "{{ .Hash }}"
+{{ end }}
 `
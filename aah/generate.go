@@ -38,25 +38,263 @@ var generateCmd = cli.Command{
 		cli.Command{
 			Name:    "script",
 			Aliases: []string{"s"},
-			Usage:   "Generates complement scripts such as systemd, dockerize, etc.",
-			Description: `Generates complement scripts such as systemd, dockerize, etc.
+			Usage:   "Generates complement scripts such as systemd, dockerize, k8s, etc.",
+			Description: `Generates complement scripts such as systemd, dockerize, k8s, etc.
+
+	Script 'k8s' generates Kubernetes Deployment, Service, ConfigMap and optional Ingress into
+	'k8s/base' and kustomize overlay for each environment profile of 'config/env' into
+	'k8s/overlays/<profile>'. ConfigMap is mounted and supplied to application via '-config' flag,
+	probes use the path of health route. Base deployment runs with profile 'prod' unless '--profile'
+	is given, each overlay runs with its own environment profile.
 
 	Example of script command:
 		aah g s -n systemd -i github.com/user/appname
 		aah generate script --name systemd --importpath github.com/user/appname
+		aah generate script --name k8s --image registry.example.com/appname:1.0 --ingress app.example.com
 			`,
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  "n, name",
-					Usage: "Provide script name such as 'systemd', 'docker', etc",
+					Usage: "Provide script name such as 'systemd', 'docker', 'k8s', etc",
 				},
 				cli.StringFlag{
 					Name:  "i, importpath",
 					Usage: "Import path of aah application",
 				},
+				cli.StringFlag{
+					Name:  "image",
+					Usage: "Container image of 'k8s' deployment, default is '<appname>:latest'",
+				},
+				cli.StringFlag{
+					Name:  "ingress",
+					Usage: "Host name of 'k8s' ingress, ingress is generated only if provided",
+				},
+				cli.StringFlag{
+					Name:  "profile",
+					Usage: "Environment profile of 'k8s' base deployment, default is 'prod'",
+				},
+				cli.StringFlag{
+					Name:  "health-route",
+					Usage: "Route name of 'k8s' readiness and liveness probes, default is 'health'",
+				},
 			},
 			Action: generateScriptsAction,
 		},
+		cli.Command{
+			Name:    "openapi",
+			Aliases: []string{"oa"},
+			Usage:   "Generates OpenAPI 3 spec from routes and controller action signatures",
+			Description: `Generates OpenAPI 3 spec (JSON) from the application route table, controller action
+	parameter names and types and its doc comments. Struct type parameters of POST, PUT and PATCH
+	routes are documented as JSON request body, otherwise as query parameters.
+
+	By default spec is written to '<app-base-dir>/openapi.json'. Use '--static' to write the
+	spec into 'static' directory, so it gets embedded into application binary.
+
+	Example of openapi command:
+		aah g oa -i github.com/user/appname
+		aah generate openapi --importpath github.com/user/appname --output /path/to/api.json
+		aah generate openapi --static
+			`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				cli.StringFlag{
+					Name:  "o, output",
+					Usage: "Output file path of OpenAPI spec",
+				},
+				cli.BoolFlag{
+					Name:  "static",
+					Usage: "Writes the OpenAPI spec into 'static' directory",
+				},
+			},
+			Action: generateOpenAPIAction,
+		},
+		cli.Command{
+			Name:    "client",
+			Aliases: []string{"c"},
+			Usage:   "Generates typed API client from routes and controller action signatures",
+			Description: `Generates typed API client package with one method per route. Path, query and
+	body parameters are typed from controller action parameters, struct types are generated as models.
+
+	Supported languages:
+		go - Go client package with examples
+		ts - TypeScript module '<package-name>.ts' using fetch API
+
+	By default Go client is written to '<app-base-dir>/client/<package-name>' and TypeScript
+	client to '<app-base-dir>/client/ts'. Use '--static' to write the TypeScript client into 'static/js'
+	directory, so the changes are picked up by hot-reload.
+
+	Example of client command:
+		aah g c -l go -i github.com/user/appname
+		aah generate client --lang go --package apiclient --output /path/to/apiclient
+		aah generate client --lang ts --static
+			`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "l, lang",
+					Usage: "Client language such as 'go', 'ts'",
+				},
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				cli.StringFlag{
+					Name:  "p, package",
+					Usage: "Package name of generated client, default is '<appname>client'",
+				},
+				cli.StringFlag{
+					Name:  "o, output",
+					Usage: "Output directory of generated client",
+				},
+				cli.BoolFlag{
+					Name:  "static",
+					Usage: "Writes the client into 'static/js' directory, only for '--lang ts'",
+				},
+			},
+			Action: generateClientAction,
+		},
+		cli.Command{
+			Name:      "controller",
+			Aliases:   []string{"ctrl"},
+			Usage:     "Generates controller with actions, view stubs and routes.conf entries",
+			ArgsUsage: "<name>",
+			Description: `Generates controller embedding 'aah.Context' into 'app/controllers', name could have
+	sub package e.g. 'admin/User' creates 'UserController' in 'app/controllers/admin/user.go'.
+
+	Actions 'index', 'new', 'create', 'show', 'edit', 'update' and 'delete' are mapped to RESTful
+	routes e.g. 'show' is 'GET /admin/users/:id', other actions to 'GET /admin/users/<action>'.
+	Routes are added into 'routes' section of domain in 'config/routes.conf' and view stubs are
+	created for GET actions of web application.
+
+	Example of controller command:
+		aah g ctrl User
+		aah g ctrl admin/User -a index,show,create
+		aah generate controller admin/User --actions index,show,create --domain localhost --no-views
+			`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				cli.StringFlag{
+					Name:  "a, actions",
+					Usage: "Comma separated action names, default is 'index'",
+				},
+				cli.StringFlag{
+					Name:  "d, domain",
+					Usage: "Domain key of 'routes.conf' to add routes, default is first domain",
+				},
+				cli.BoolFlag{
+					Name:  "no-views",
+					Usage: "Skips the view stubs creation",
+				},
+			},
+			Action: generateControllerAction,
+		},
+		cli.Command{
+			Name:      "websocket",
+			Aliases:   []string{"ws"},
+			Usage:     "Generates WebSocket handler, routes.conf entry and test page",
+			ArgsUsage: "<name>",
+			Description: `Generates WebSocket handler embedding 'ws.Context' into 'app/websockets' with message
+	action and lifecycle events func. Route 'WS /ws/<name>' is added into 'routes' section of domain
+	in 'config/routes.conf' and test page 'static/<name>_ws.html' is created for manually
+	exercising the WebSocket.
+
+	Example of websocket command:
+		aah g ws Chat
+		aah g ws Chat -m json
+		aah generate websocket Chat --message json --domain localhost
+			`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				cli.StringFlag{
+					Name:  "m, message",
+					Usage: "Message type of WebSocket action 'text' or 'json', default is 'text'",
+				},
+				cli.StringFlag{
+					Name:  "d, domain",
+					Usage: "Domain key of 'routes.conf' to add route, default is first domain",
+				},
+			},
+			Action: generateWebSocketAction,
+		},
+		cli.Command{
+			Name:    "auth",
+			Aliases: []string{"a"},
+			Usage:   "Generates authenticator, principal provider, authorizer and auth scheme config",
+			Description: `Generates Go types implementing 'authc.Authenticator', 'authc.PrincipalProvider' and
+	'authz.Authorizer' into 'app/security/<scheme>.go', adds the auth scheme block into
+	'security.auth_schemes' of 'config/security.conf' and example protected routes
+	'/<name>/secured' and '/<name>/secured/admin' into 'config/routes.conf'.
+
+	Supported auth scheme types are 'form', 'basic', 'generic' and 'oauth2'.
+
+	Example of auth command:
+		aah g auth
+		aah g auth -s api_auth -t generic
+		aah generate auth --scheme form_auth --type form --domain localhost
+			`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				cli.StringFlag{
+					Name:  "s, scheme",
+					Usage: "Auth scheme name, default is '<type>_auth'",
+				},
+				cli.StringFlag{
+					Name:  "t, type",
+					Usage: "Auth scheme type 'form', 'basic', 'generic' or 'oauth2', default is 'form'",
+				},
+				cli.StringFlag{
+					Name:  "d, domain",
+					Usage: "Domain key of 'routes.conf' to add routes, default is first domain",
+				},
+			},
+			Action: generateAuthAction,
+		},
+		cli.Command{
+			Name:      "resource",
+			Aliases:   []string{"r"},
+			Usage:     "Generates CRUD resource with model, repository, controller, views and routes",
+			ArgsUsage: "<name> [field:type...]",
+			Description: `Generates CRUD resource for given name and fields, field types are 'string', 'text',
+	'int', 'int64', 'float', 'float64' and 'bool', default is 'string'. It creates model and
+	in-memory repository interface implementation into 'app/models', controller with 'index',
+	'show', 'new', 'create', 'edit', 'update' and 'destroy' actions into 'app/controllers',
+	views with application view delimiters and RESTful routes into 'config/routes.conf'.
+
+	API application (or '--api') gets JSON-only handlers without 'new', 'edit' and views.
+
+	Example of resource command:
+		aah g r Product name:string price:float
+		aah g r admin/Product name description:text price:float active:bool
+		aah generate resource Product name:string price:float --api --domain localhost
+			`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				cli.StringFlag{
+					Name:  "d, domain",
+					Usage: "Domain key of 'routes.conf' to add routes, default is first domain",
+				},
+				cli.BoolFlag{
+					Name:  "api",
+					Usage: "Generates JSON-only handlers, default for application without 'views' directory",
+				},
+			},
+			Action: generateResourceAction,
+		},
 	},
 }
 
@@ -77,8 +315,10 @@ func generateScriptsAction(c *cli.Context) error {
 		err = generateSystemdScript(c)
 	case "docker":
 		err = generateDockerScript(c)
+	case "k8s":
+		err = generateK8sScript(c)
 	default:
-		log.Error("Unsupported 'script' name, try one of these 'systemd', 'docker'")
+		log.Error("Unsupported 'script' name, try one of these 'systemd', 'docker', 'k8s'")
 	}
 
 	if err != nil {
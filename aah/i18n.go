@@ -0,0 +1,548 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var i18nCmd = cli.Command{
+	Name:  "i18n",
+	Usage: "Checks i18n message files completeness and extracts missing keys",
+	Description: `Command i18n scans the 'views' and Go source under 'app' for i18n key usages and
+	compares them with every locale message file in 'i18n'.
+
+	Usages are '{{ i18n . "key" args }}' in views, 'ctx.Msg("key", args)' and
+	'aah.AppI18n().Lookup(locale, "key", args)' in Go source. Keys given via variable
+	cannot be detected, so unused keys are reported as warning.
+
+	To know more about individual sub-commands details:
+		aah i18n help check
+		aah i18n help extract
+`,
+	Subcommands: []cli.Command{
+		cli.Command{
+			Name:    "check",
+			Aliases: []string{"c"},
+			Usage:   "Reports missing keys per locale, unused keys and placeholder mismatches",
+			Description: `Reports i18n keys missing per locale, keys not translated (empty value), unused keys
+	and placeholder mismatches between locales and with usage arguments. Exit code is non-zero
+	if any error found.
+
+	Examples of short and long flags:
+		aah i18n check
+		aah i18n check -i github.com/user/appname
+		aah i18n check --format json`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				diagFormatFlag,
+			},
+			Action: i18nCheckAction,
+		},
+		cli.Command{
+			Name:    "extract",
+			Aliases: []string{"e"},
+			Usage:   "Writes skeleton entries for the missing keys of each locale",
+			Description: `Writes skeleton entries for the missing keys of each locale into 'i18n/<name>.<locale>'.
+	Entries are commented out with the default locale message as hint, so the empty values
+	are not loaded by application. Uncomment and translate them or move into the message
+	file of the locale. Running extract again keeps the entries of the file and adds newly
+	missing keys.
+
+	Examples of short and long flags:
+		aah i18n extract
+		aah i18n e -i github.com/user/appname -n untranslated
+		aah i18n extract --importpath github.com/user/appname --name untranslated`,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "i, importpath",
+					Usage: "Import path of aah application",
+				},
+				cli.StringFlag{
+					Name:  "n, name",
+					Usage: "File name of skeleton entries, locale is added as extension",
+					Value: "missing",
+				},
+			},
+			Action: i18nExtractAction,
+		},
+	},
+}
+
+// i18nVerbRegex matches the fmt verbs of i18n message e.g. '%s', '%5.2f'.
+var i18nVerbRegex = regexp.MustCompile(`%(?:%|[-+# 0]*(?:\d+|\*)?(?:\.(?:\d+|\*)?)?[a-zA-Z])`)
+
+type (
+	// i18nUsage is the i18n key usage found in views or Go source. Args is
+	// -1 when arguments count is not known e.g. 'args...'.
+	i18nUsage struct {
+		Key    string
+		File   string
+		Line   int
+		Column int
+		Args   int
+	}
+
+	// i18nMessage is the message key defined in the locale file.
+	i18nMessage struct {
+		Key   string
+		Value string
+		File  string
+		Line  int
+	}
+
+	// i18nLocale holds the messages of locale from all the message files.
+	i18nLocale struct {
+		Name     string
+		Messages map[string]*i18nMessage
+	}
+
+	i18nProject struct {
+		baseDir       string
+		defaultLocale string
+		locales       map[string]*i18nLocale
+		usages        []*i18nUsage
+		diags         []*diagnostic
+	}
+)
+
+func i18nCheckAction(c *cli.Context) error {
+	ip := loadI18nProject(appImportPath(c))
+	ip.check()
+
+	sortDiagnostics(ip.diags)
+	printDiagnostics(strings.ToLower(c.String("format")), ip.diags)
+
+	var errCnt int
+	for _, d := range ip.diags {
+		if d.Severity == diagError {
+			errCnt++
+		}
+	}
+
+	if errCnt > 0 {
+		cliLog.Errorf("i18n check failed with %d error(s), %d warning(s)", errCnt, len(ip.diags)-errCnt)
+		exit(1)
+	}
+	cliLog.Infof("i18n check successful, %d warning(s)\n", len(ip.diags))
+	return nil
+}
+
+func i18nExtractAction(c *cli.Context) error {
+	ip := loadI18nProject(appImportPath(c))
+	name := firstNonEmpty(c.String("n"), c.String("name"), "missing")
+
+	var count int
+	for _, locale := range ip.localeNames() {
+		missing := ip.missingKeys(locale)
+		if len(missing) == 0 {
+			continue
+		}
+
+		file := filepath.Join(ip.baseDir, "i18n", name+"."+locale)
+		entries := make(map[string]string)
+		for _, m := range ip.locales[locale].Messages {
+			if m.File == file {
+				entries[m.Key] = m.Value
+			}
+		}
+
+		// missing keys are written as commented out entries, file resides in
+		// 'i18n' directory and empty values would be loaded by application
+		pending := make(map[string]bool)
+		for _, key := range missing {
+			entries[key] = ""
+			pending[key] = true
+		}
+
+		buf := &bytes.Buffer{}
+		fmt.Fprintf(buf, "# Generated by 'aah i18n extract' for locale '%s', uncomment and translate\n", locale)
+		fmt.Fprintf(buf, "# the entries or move them into message file of the locale.\n\n")
+		writeI18nEntries(buf, entries, ip.hints(locale), pending, 0)
+		if err := ioutil.WriteFile(file, buf.Bytes(), permRWRWRW); err != nil {
+			logFatal(err)
+		}
+
+		cliLog.Infof("%d missing key(s) of locale '%s' written to %s", len(missing), locale, stripGoSrcPath(file))
+		count += len(missing)
+	}
+
+	if count == 0 {
+		cliLog.Info("No missing i18n keys found")
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// loadI18nProject method loads the locale message files and i18n key usages
+// of the application.
+func loadI18nProject(importPath string) *i18nProject {
+	cliLog = initCLILogger(nil)
+	baseDir := filepath.Join(gosrcDir, filepath.FromSlash(importPath))
+	if !isAahProject(filepath.Join(baseDir, aahProjectIdentifier)) {
+		logFatalf("'%s' is not a valid aah application, missing 'aah.project' file", importPath)
+	}
+
+	appCfg, err := config.LoadFile(filepath.Join(baseDir, "config", "aah.conf"))
+	if err != nil {
+		logFatalf("Unable to load 'config/aah.conf': %s", err)
+	}
+
+	ip := &i18nProject{
+		baseDir:       baseDir,
+		defaultLocale: appCfg.StringDefault("i18n.default", "en"),
+		locales:       make(map[string]*i18nLocale),
+	}
+	ip.loadLocales(filepath.Join(baseDir, "i18n"))
+	if len(ip.locales) == 0 {
+		logFatalf("No i18n message files found in '%s'", filepath.Join(baseDir, "i18n"))
+	}
+	if _, found := ip.locales[ip.defaultLocale]; !found {
+		cliLog.Warnf("Default locale '%s' message file not found, using '%s'", ip.defaultLocale, ip.localeNames()[0])
+		ip.defaultLocale = ip.localeNames()[0]
+	}
+
+	delims := strings.Split(appCfg.StringDefault("view.delimiters", "{{.}}"), ".")
+	if len(delims) != 2 {
+		delims = []string{"{{", "}}"}
+	}
+	ip.scanViews(filepath.Join(baseDir, "views"), appCfg.StringDefault("view.ext", ".html"), delims[0], delims[1])
+	ip.scanGoSource(filepath.Join(baseDir, "app"))
+
+	cliLog.Infof("Found %d locale(s) and %d i18n key usage(s) in '%s'", len(ip.locales), len(ip.usages), importPath)
+	return ip
+}
+
+// loadLocales method loads the message files, file extension is the locale
+// e.g. 'messages.en-US'. Messages of same locale from multiple files are
+// merged.
+func (ip *i18nProject) loadLocales(dir string) {
+	files, _ := ess.FilesPath(dir, true)
+	for _, f := range files {
+		locale := strings.TrimPrefix(filepath.Ext(f), ".")
+		if ess.IsStrEmpty(locale) {
+			continue
+		}
+
+		cfg, err := config.LoadFile(f)
+		if err != nil {
+			ip.errorf(f, 0, 0, "%s", err)
+			continue
+		}
+		keys, err := scanConfFile(f)
+		if err != nil {
+			ip.errorf(f, 0, 0, "%s", err)
+			continue
+		}
+
+		l, found := ip.locales[locale]
+		if !found {
+			l = &i18nLocale{Name: locale, Messages: make(map[string]*i18nMessage)}
+			ip.locales[locale] = l
+		}
+		for _, k := range keys.Leaves() {
+			l.Messages[k.Path] = &i18nMessage{Key: k.Path, Value: cfg.StringDefault(k.Path, ""), File: f, Line: k.Line}
+		}
+	}
+}
+
+// scanViews method finds the '{{ i18n . "key" args }}' usages in views.
+func (ip *i18nProject) scanViews(dir, ext, leftDelim, rightDelim string) {
+	files, _ := ess.FilesPath(dir, true)
+	for _, f := range files {
+		if filepath.Ext(f) != ext {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			continue
+		}
+
+		// view functions are not known here, view validation reports them
+		t := parse.New(f)
+		t.Mode = parse.SkipFuncCheck
+		trees := make(map[string]*parse.Tree)
+		if _, err = t.Parse(string(b), leftDelim, rightDelim, trees); err != nil {
+			cliLog.Debugf("Skipping view file %s: %s", f, err)
+			continue
+		}
+
+		for _, tree := range trees {
+			walkTemplateNodes(tree.Root, func(n parse.Node) {
+				cmd, ok := n.(*parse.CommandNode)
+				if !ok || len(cmd.Args) < 3 {
+					return
+				}
+				if ident, ok := cmd.Args[0].(*parse.IdentifierNode); !ok || ident.Ident != "i18n" {
+					return
+				}
+				if str, ok := cmd.Args[2].(*parse.StringNode); ok {
+					line, col := templateNodePos(tree, str)
+					ip.usages = append(ip.usages, &i18nUsage{Key: str.Text, File: f, Line: line,
+						Column: col, Args: len(cmd.Args) - 3})
+				}
+			})
+		}
+	}
+}
+
+// scanGoSource method finds the 'Msg("key", args)' and
+// 'AppI18n().Lookup(locale, "key", args)' usages in Go source.
+func (ip *i18nProject) scanGoSource(dir string) {
+	fset := token.NewFileSet()
+	files, _ := ess.FilesPath(dir, true)
+	for _, f := range files {
+		if filepath.Ext(f) != ".go" || strings.HasSuffix(f, "_test.go") || filepath.Base(f) == "aah.go" {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, f, nil, 0)
+		if err != nil {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			keyIdx := -1
+			switch sel.Sel.Name {
+			case "Msg":
+				keyIdx = 0
+			case "Lookup":
+				if x, ok := sel.X.(*ast.CallExpr); ok {
+					if xsel, ok := x.Fun.(*ast.SelectorExpr); ok && xsel.Sel.Name == "AppI18n" {
+						keyIdx = 1
+					}
+				}
+			}
+			if keyIdx == -1 || len(call.Args) <= keyIdx {
+				return true
+			}
+
+			lit, ok := call.Args[keyIdx].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			key, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+
+			args := len(call.Args) - keyIdx - 1
+			if call.Ellipsis.IsValid() {
+				args = -1
+			}
+			pos := fset.Position(lit.Pos())
+			ip.usages = append(ip.usages, &i18nUsage{Key: key, File: f, Line: pos.Line, Column: pos.Column, Args: args})
+			return true
+		})
+	}
+}
+
+// check method reports missing and not translated keys per locale, unused
+// keys and placeholder mismatches.
+func (ip *i18nProject) check() {
+	used := make(map[string]bool)
+	for _, u := range ip.usages {
+		used[u.Key] = true
+
+		var missing, empty []string
+		for _, locale := range ip.localeNames() {
+			m, found := ip.locales[locale].Messages[u.Key]
+			switch {
+			case !found:
+				missing = append(missing, locale)
+			case ess.IsStrEmpty(m.Value):
+				empty = append(empty, locale)
+			case u.Args >= 0 && len(i18nVerbs(m.Value)) != u.Args:
+				ip.errorf(u.File, u.Line, u.Column, "i18n key %q message of locale '%s' has %d placeholder(s), however %d argument(s) given",
+					u.Key, locale, len(i18nVerbs(m.Value)), u.Args)
+			}
+		}
+
+		if len(missing) > 0 {
+			ip.errorf(u.File, u.Line, u.Column, "i18n key %q is missing in locale(s): %s", u.Key, strings.Join(missing, ", "))
+		}
+		if len(empty) > 0 {
+			ip.warnf(u.File, u.Line, u.Column, "i18n key %q is not translated in locale(s): %s", u.Key, strings.Join(empty, ", "))
+		}
+	}
+
+	defaults := ip.locales[ip.defaultLocale].Messages
+	for _, locale := range ip.localeNames() {
+		for _, key := range sortedI18nKeys(ip.locales[locale].Messages) {
+			m := ip.locales[locale].Messages[key]
+			if !used[key] {
+				ip.warnf(m.File, m.Line, 0, "i18n key %q is unused", key)
+			}
+
+			if dm, found := defaults[key]; found && locale != ip.defaultLocale && !ess.IsStrEmpty(m.Value) {
+				if dv, v := i18nVerbs(dm.Value), i18nVerbs(m.Value); strings.Join(dv, " ") != strings.Join(v, " ") {
+					ip.errorf(m.File, m.Line, 0, "i18n key %q placeholders [%s] does not match with default locale '%s' [%s]",
+						key, strings.Join(v, " "), ip.defaultLocale, strings.Join(dv, " "))
+				}
+			}
+		}
+
+		if missing := len(ip.missingKeys(locale)); missing > 0 {
+			cliLog.Warnf("Locale '%s' is missing %d key(s)", locale, missing)
+		}
+	}
+}
+
+// missingKeys method returns the sorted keys used in application however not
+// defined in the locale.
+func (ip *i18nProject) missingKeys(locale string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, u := range ip.usages {
+		if _, found := ip.locales[locale].Messages[u.Key]; !found && !seen[u.Key] {
+			seen[u.Key] = true
+			keys = append(keys, u.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hints method returns the default locale messages as hint for skeleton
+// entries of other locale.
+func (ip *i18nProject) hints(locale string) map[string]string {
+	hints := make(map[string]string)
+	if locale == ip.defaultLocale {
+		return hints
+	}
+	for key, m := range ip.locales[ip.defaultLocale].Messages {
+		hints[key] = ip.defaultLocale + ": " + m.Value
+	}
+	return hints
+}
+
+func (ip *i18nProject) localeNames() []string {
+	var names []string
+	for name := range ip.locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (ip *i18nProject) errorf(file string, line, col int, format string, v ...interface{}) {
+	ip.addDiag(diagError, file, line, col, fmt.Sprintf(format, v...))
+}
+
+func (ip *i18nProject) warnf(file string, line, col int, format string, v ...interface{}) {
+	ip.addDiag(diagWarning, file, line, col, fmt.Sprintf(format, v...))
+}
+
+func (ip *i18nProject) addDiag(severity, file string, line, col int, msg string) {
+	ip.diags = append(ip.diags, &diagnostic{Origin: diagOriginI18n, File: diagRelPath(ip.baseDir, file),
+		Line: line, Column: col, Severity: severity, Message: msg, Excerpt: sourceLine(file, line)})
+}
+
+// i18nVerbs method returns the fmt verbs of message, escaped percent '%%' is
+// not a verb.
+func i18nVerbs(msg string) []string {
+	var verbs []string
+	for _, v := range i18nVerbRegex.FindAllString(msg, -1) {
+		if v != "%%" {
+			verbs = append(verbs, v)
+		}
+	}
+	return verbs
+}
+
+func sortedI18nKeys(messages map[string]*i18nMessage) []string {
+	var keys []string
+	for key := range messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeI18nEntries method writes the entries as nested sections of aah
+// config format, entry with hint gets it as comment and pending entry is
+// written commented out.
+func writeI18nEntries(buf *bytes.Buffer, entries, hints map[string]string, pending map[string]bool, depth int) {
+	indent := strings.Repeat("  ", depth)
+	sections := make(map[string]map[string]string)
+	var keys []string
+	for key, value := range entries {
+		idx := strings.Index(key, ".")
+		if idx == -1 {
+			keys = append(keys, key)
+			continue
+		}
+
+		name := key[:idx]
+		if _, found := sections[name]; !found {
+			sections[name] = make(map[string]string)
+			keys = append(keys, name+".")
+		}
+		sections[name][key[idx+1:]] = value
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if name := strings.TrimSuffix(key, "."); name != key {
+			sectionHints := make(map[string]string)
+			for k, v := range hints {
+				if strings.HasPrefix(k, key) {
+					sectionHints[k[len(key):]] = v
+				}
+			}
+			sectionPending := make(map[string]bool)
+			for k := range pending {
+				if strings.HasPrefix(k, key) {
+					sectionPending[k[len(key):]] = true
+				}
+			}
+			fmt.Fprintf(buf, "%s%s {\n", indent, name)
+			writeI18nEntries(buf, sections[name], sectionHints, sectionPending, depth+1)
+			fmt.Fprintf(buf, "%s}\n", indent)
+			continue
+		}
+
+		if hint, found := hints[key]; found {
+			fmt.Fprintf(buf, "%s# %s\n", indent, strings.Replace(hint, "\n", " ", -1))
+		}
+		if pending[key] {
+			fmt.Fprintf(buf, "%s#%s = %s\n", indent, key, strconv.Quote(entries[key]))
+			continue
+		}
+		fmt.Fprintf(buf, "%s%s = %s\n", indent, key, strconv.Quote(entries[key]))
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// k8sManifest holds the values of Kubernetes manifests derived from
+// application.
+type k8sManifest struct {
+	Name        string
+	AppName     string
+	BinaryName  string
+	Image       string
+	Port        string
+	Profile     string
+	ConfigDir   string
+	ConfigFile  string
+	HealthPath  string
+	IngressHost string
+	CreateDate  string
+	Profiles    []string
+}
+
+func generateK8sScript(c *cli.Context) error {
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+
+	cliLog.Infof("Loaded aah project file: %s\n", filepath.Join(aah.AppBaseDir(), aahProjectIdentifier))
+
+	k8sDir := filepath.Join(aah.AppBaseDir(), "k8s")
+	if checkAndConfirmOverwrite(c, k8sDir) {
+		return nil
+	}
+
+	name := k8sName(aah.AppName())
+	m := &k8sManifest{
+		Name:        name,
+		AppName:     aah.AppName(),
+		BinaryName:  strings.TrimSuffix(filepath.Base(appBinaryFile(projectCfg, "")), ".exe"),
+		Image:       firstNonEmpty(c.String("image"), name+":latest"),
+		Port:        firstNonEmpty(aah.AppHTTPPort(), "8080"),
+		Profile:     firstNonEmpty(c.String("profile"), "prod"),
+		ConfigDir:   path.Join("/etc", name),
+		ConfigFile:  "external.conf",
+		IngressHost: c.String("ingress"),
+		CreateDate:  time.Now().Format(time.RFC1123Z),
+		Profiles:    appEnvProfiles(aah.AppBaseDir()),
+	}
+
+	healthRoute := firstNonEmpty(c.String("health-route"), "health")
+	if m.HealthPath = routePathByName(healthRoute); ess.IsStrEmpty(m.HealthPath) {
+		cliLog.Warnf("Route '%s' not found in 'config/routes.conf', probes use TCP socket check", healthRoute)
+	}
+
+	files := map[string]string{
+		path.Join("base", "deployment.yaml"):    aahK8sDeploymentTemplate,
+		path.Join("base", "service.yaml"):       aahK8sServiceTemplate,
+		path.Join("base", "configmap.yaml"):     aahK8sConfigMapTemplate,
+		path.Join("base", "kustomization.yaml"): aahK8sBaseKustomizationTemplate,
+	}
+	if !ess.IsStrEmpty(m.IngressHost) {
+		files[path.Join("base", "ingress.yaml")] = aahK8sIngressTemplate
+	}
+	for _, profile := range m.Profiles {
+		files[path.Join("overlays", profile, "kustomization.yaml")] = aahK8sOverlayKustomizationTemplate
+		files[path.Join("overlays", profile, m.ConfigFile)] = aahK8sOverlayConfigTemplate
+	}
+
+	var names []string
+	for f := range files {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	for _, f := range names {
+		buf.Reset()
+		data := map[string]interface{}{"M": m, "FileName": f, "Profile": path.Base(path.Dir(f))}
+		if err := renderTmpl(buf, files[f], data); err != nil {
+			return fmt.Errorf("Unable to create %s: %s", f, err)
+		}
+
+		destFile := filepath.Join(k8sDir, filepath.FromSlash(f))
+		if err := ess.MkDirAll(filepath.Dir(destFile), permRWXRXRX); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(destFile, buf.Bytes(), permRWRWRW); err != nil {
+			return fmt.Errorf("Unable to create %s: %s", f, err)
+		}
+	}
+
+	cliLog.Infof("Generated Kubernetes manifests at %s\n\t%s\n", k8sDir, strings.Join(names, "\n\t"))
+	applyDir := filepath.Join(k8sDir, "base")
+	if ess.IsSliceContainsString(m.Profiles, m.Profile) {
+		applyDir = filepath.Join(k8sDir, "overlays", m.Profile)
+	}
+	cliLog.Infof("What's next, build the image with 'aah generate script -n docker' and apply e.g. 'kubectl apply -k %s'\n", applyDir)
+
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// k8sName method returns the DNS-1123 compliant name of Kubernetes object.
+func k8sName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	if n := strings.Trim(b.String(), "-"); !ess.IsStrEmpty(n) {
+		return n
+	}
+	return "aah-app"
+}
+
+// appEnvProfiles method returns the environment profile names from
+// 'config/env/*.conf'.
+func appEnvProfiles(baseDir string) []string {
+	files, _ := filepath.Glob(filepath.Join(baseDir, "config", "env", "*.conf"))
+	var profiles []string
+	for _, f := range files {
+		profiles = append(profiles, ess.StripExt(filepath.Base(f)))
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// routePathByName method returns the path of route name from application
+// domains, root domain is looked up first.
+func routePathByName(name string) string {
+	if domain := aah.AppRouter().RootDomain(); domain != nil {
+		if route := domain.LookupByName(name); route != nil {
+			return route.Path
+		}
+	}
+
+	var keys []string
+	for key := range aah.AppRouter().Domains {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if route := aah.AppRouter().Domains[key].LookupByName(name); route != nil {
+			return route.Path
+		}
+	}
+	return ""
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Kubernetes Templates
+//___________________________________
+
+const aahK8sDeploymentTemplate = `# GENERATED BY aah CLI - Feel free to customization it.
+# FILE: {{ .FileName }}
+# DATE: {{ .M.CreateDate }}
+# DESC: aah application Kubernetes deployment, image is built from 'Dockerfile.prod'
+{{- $m := .M }}
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ $m.Name }}
+  labels:
+    app: {{ $m.Name }}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: {{ $m.Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ $m.Name }}
+    spec:
+      containers:
+        - name: {{ $m.Name }}
+          image: {{ $m.Image }}
+          command: ["./bin/{{ $m.BinaryName }}"]
+          args: ["-profile", "{{ $m.Profile }}", "-config", "{{ $m.ConfigDir }}/{{ $m.ConfigFile }}"]
+          ports:
+            - name: http
+              containerPort: {{ $m.Port }}
+{{- if $m.HealthPath }}
+          readinessProbe:
+            httpGet:
+              path: {{ $m.HealthPath }}
+              port: http
+            initialDelaySeconds: 5
+            periodSeconds: 10
+          livenessProbe:
+            httpGet:
+              path: {{ $m.HealthPath }}
+              port: http
+            initialDelaySeconds: 15
+            periodSeconds: 20
+{{- else }}
+          readinessProbe:
+            tcpSocket:
+              port: http
+            initialDelaySeconds: 5
+            periodSeconds: 10
+          livenessProbe:
+            tcpSocket:
+              port: http
+            initialDelaySeconds: 15
+            periodSeconds: 20
+{{- end }}
+          volumeMounts:
+            - name: config
+              mountPath: {{ $m.ConfigDir }}
+              readOnly: true
+      volumes:
+        - name: config
+          configMap:
+            name: {{ $m.Name }}-config
+`
+
+const aahK8sServiceTemplate = `# GENERATED BY aah CLI - Feel free to customization it.
+# FILE: {{ .FileName }}
+# DATE: {{ .M.CreateDate }}
+# DESC: aah application Kubernetes service
+{{- $m := .M }}
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ $m.Name }}
+  labels:
+    app: {{ $m.Name }}
+spec:
+  selector:
+    app: {{ $m.Name }}
+  ports:
+    - name: http
+      port: 80
+      targetPort: http
+`
+
+const aahK8sConfigMapTemplate = `# GENERATED BY aah CLI - Feel free to customization it.
+# FILE: {{ .FileName }}
+# DATE: {{ .M.CreateDate }}
+# DESC: aah application external config, supplied via '-config' flag and
+# merged into application config
+{{- $m := .M }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ $m.Name }}-config
+  labels:
+    app: {{ $m.Name }}
+data:
+  {{ $m.ConfigFile }}: |
+    # External config of '{{ $m.AppName }}', it overrides 'aah.conf' values e.g.
+    # secrets, database URL, etc.
+`
+
+const aahK8sIngressTemplate = `# GENERATED BY aah CLI - Feel free to customization it.
+# FILE: {{ .FileName }}
+# DATE: {{ .M.CreateDate }}
+# DESC: aah application Kubernetes ingress
+{{- $m := .M }}
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{ $m.Name }}
+  labels:
+    app: {{ $m.Name }}
+spec:
+  rules:
+    - host: {{ $m.IngressHost }}
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: {{ $m.Name }}
+                port:
+                  name: http
+`
+
+const aahK8sBaseKustomizationTemplate = `# GENERATED BY aah CLI - Feel free to customization it.
+# FILE: {{ .FileName }}
+# DATE: {{ .M.CreateDate }}
+# DESC: aah application kustomize base
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - deployment.yaml
+  - service.yaml
+  - configmap.yaml
+{{- if .M.IngressHost }}
+  - ingress.yaml
+{{- end }}
+`
+
+const aahK8sOverlayKustomizationTemplate = `# GENERATED BY aah CLI - Feel free to customization it.
+# FILE: {{ .FileName }}
+# DATE: {{ .M.CreateDate }}
+# DESC: aah application kustomize overlay of environment profile '{{ .Profile }}'
+{{- $m := .M }}
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - ../../base
+commonLabels:
+  env: {{ .Profile }}
+configMapGenerator:
+  - name: {{ $m.Name }}-config
+    behavior: replace
+    files:
+      - {{ $m.ConfigFile }}
+patches:
+  - target:
+      kind: Deployment
+      name: {{ $m.Name }}
+    patch: |-
+      - op: replace
+        path: /spec/template/spec/containers/0/args
+        value: ["-profile", "{{ .Profile }}", "-config", "{{ $m.ConfigDir }}/{{ $m.ConfigFile }}"]
+`
+
+const aahK8sOverlayConfigTemplate = `# External config of '{{ .M.AppName }}' for environment profile '{{ .Profile }}',
+# it overrides 'aah.conf' values e.g. secrets, database URL, etc.
+`
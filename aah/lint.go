@@ -0,0 +1,283 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/ainsp.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/router.v0"
+)
+
+// lintIgnoreUnused is the annotation to exclude the controller or method
+// from dead action report, it is placed in the doc comment.
+const lintIgnoreUnused = "aah:ignore-unused"
+
+var lintInterceptorPrefixes = []string{"Before", "After", "Finally", "Panic"}
+
+var lintCmd = cli.Command{
+	Name:  "lint",
+	Usage: "Reports dead and unimplemented actions of aah application",
+	Description: `Command lint reports the issues between 'routes.conf' and application code.
+
+		* Dead actions - exported controller and websocket methods which are not referenced
+		  by any route in 'routes.conf'. Default actions and interceptors are excluded.
+		* Unimplemented actions - actions configured in 'routes.conf', however not implemented.
+
+	To exclude the controller or method from dead action report, add annotation
+	'// aah:ignore-unused' into its doc comment.
+
+	Use '--fail' to exit with non-zero code if any issue is found, e.g. on CI.
+
+	Examples of short and long flags:
+		aah lint
+		aah lint -i github.com/user/appname --fail`,
+	Action: lintAction,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "i, importpath",
+			Usage: "Import path of aah application",
+		},
+		cli.BoolFlag{
+			Name:  "fail",
+			Usage: "Exits with non-zero code if any issue is found",
+		},
+	},
+}
+
+// lintMethod holds the method declaration info from Go source.
+type lintMethod struct {
+	Pos    token.Position
+	Ignore bool
+}
+
+func lintAction(c *cli.Context) error {
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+	cliLog.Infof("Lint starts for '%s' [%s]", aah.AppName(), aah.AppImportPath())
+
+	actions, err := inspectAppActions(projectCfg)
+	if err != nil {
+		logFatal(err)
+	}
+
+	routes, err := appRouteTable(actions)
+	if err != nil {
+		logFatal(err)
+	}
+
+	var unimplemented []string
+	referenced := make(map[*ainsp.MethodInfo]bool)
+	for _, r := range routes {
+		if r.Static {
+			continue
+		}
+		if m := actions.lookup(r); m != nil {
+			referenced[m] = true
+		} else if !r.Implemented {
+			unimplemented = append(unimplemented, fmt.Sprintf("routes.conf: route '%s' => %s.%s is not implemented",
+				r.Name, r.Controller, r.Action))
+		}
+	}
+
+	appCodeDir := filepath.Join(aah.AppBaseDir(), "app")
+	decls := make(map[string]*lintMethod)
+	for _, dir := range []string{filepath.Join(appCodeDir, "controllers"), filepath.Join(appCodeDir, "websockets")} {
+		if err = parseMethodDecls(dir, decls); err != nil {
+			logFatal(err)
+		}
+	}
+
+	var dead []string
+	for _, t := range append(actions.Controllers, actions.WebSockets...) {
+		dead = append(dead, deadActions(t, referenced, decls)...)
+	}
+	sort.Strings(dead)
+	sort.Strings(unimplemented)
+
+	if len(dead) > 0 {
+		cliLog.Warnf("Following actions are not referenced by any route in 'routes.conf':\n\t%s",
+			strings.Join(dead, "\n\t"))
+	}
+	if len(unimplemented) > 0 {
+		cliLog.Warnf("Following actions are configured in 'routes.conf', however not implemented:\n\t%s",
+			strings.Join(unimplemented, "\n\t"))
+	}
+
+	issues := len(dead) + len(unimplemented)
+	if issues == 0 {
+		cliLog.Info("Lint successful, no issues found\n")
+		return nil
+	}
+
+	cliLog.Infof("Lint found %d issue(s)\n", issues)
+	if c.Bool("fail") {
+		exit(1)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// deadActions method returns the exported methods of given type that are not
+// referenced by any route, in the format of '<file>:<line>: <type>.<method>'.
+func deadActions(t *ainsp.TypeInfo, referenced map[*ainsp.MethodInfo]bool, decls map[string]*lintMethod) []string {
+	methodNames := make(map[string]bool)
+	for _, m := range t.Methods {
+		methodNames[m.Name] = true
+	}
+
+	typeKey := t.ImportPath + "." + t.Name
+	if d, found := decls[typeKey]; found && d.Ignore {
+		return nil
+	}
+
+	var dead []string
+	for _, m := range t.Methods {
+		if referenced[m] || !isExported(m.Name) || router.IsDefaultAction(m.Name) ||
+			isInterceptor(m.Name, methodNames) {
+			continue
+		}
+
+		d, found := decls[typeKey+"."+m.Name]
+		if found && d.Ignore {
+			continue
+		}
+
+		location := path.Base(t.ImportPath)
+		if found {
+			location = fmt.Sprintf("%s:%d", filepath.ToSlash(strings.TrimPrefix(d.Pos.Filename,
+				aah.AppBaseDir()+string(filepath.Separator))), d.Pos.Line)
+		}
+		dead = append(dead, fmt.Sprintf("%s: %s.%s", location, t.Name, m.Name))
+	}
+	return dead
+}
+
+// isInterceptor method reports whether method is controller interceptor
+// e.g. 'Before', 'After', 'Finally', 'Panic' and its action specific variants
+// e.g. 'BeforeIndex'.
+func isInterceptor(name string, methodNames map[string]bool) bool {
+	for _, prefix := range lintInterceptorPrefixes {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if action := strings.TrimPrefix(name, prefix); ess.IsStrEmpty(action) || methodNames[action] {
+			return true
+		}
+	}
+	return false
+}
+
+func isExported(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// parseMethodDecls method parses the Go source files of given directory
+// recursively and collects the type and method declarations keyed by
+// '<importpath>.<type>' and '<importpath>.<type>.<method>'.
+func parseMethodDecls(dir string, decls map[string]*lintMethod) error {
+	if !ess.IsFileExists(dir) {
+		return nil
+	}
+
+	return ess.Walk(dir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(aah.AppBaseDir(), fpath)
+		if err != nil {
+			return err
+		}
+		importPath := path.Join(aah.AppImportPath(), filepath.ToSlash(rel))
+
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, fpath, func(fi os.FileInfo) bool {
+			return !strings.HasSuffix(fi.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		for _, pkg := range pkgs {
+			for _, f := range pkg.Files {
+				collectMethodDecls(fset, f, importPath, decls)
+			}
+		}
+		return nil
+	})
+}
+
+func collectMethodDecls(fset *token.FileSet, f *ast.File, importPath string, decls map[string]*lintMethod) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				decls[importPath+"."+ts.Name.Name] = &lintMethod{
+					Pos:    fset.Position(ts.Pos()),
+					Ignore: hasIgnoreAnnotation(d.Doc) || hasIgnoreAnnotation(ts.Doc),
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				continue
+			}
+
+			recv := d.Recv.List[0].Type
+			if se, ok := recv.(*ast.StarExpr); ok {
+				recv = se.X
+			}
+			ident, ok := recv.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			decls[importPath+"."+ident.Name+"."+d.Name.Name] = &lintMethod{
+				Pos:    fset.Position(d.Pos()),
+				Ignore: hasIgnoreAnnotation(d.Doc),
+			}
+		}
+	}
+}
+
+func hasIgnoreAnnotation(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.Contains(c.Text, lintIgnoreUnused) {
+			return true
+		}
+	}
+	return false
+}
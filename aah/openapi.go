@@ -0,0 +1,698 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+const openapiVersion = "3.0.3"
+
+// openapiDoc is the OpenAPI 3 document model, only the parts that can be
+// derived from aah application are modeled.
+type openapiDoc struct {
+	OpenAPI    string                       `json:"openapi"`
+	Info       *openapiInfo                 `json:"info"`
+	Servers    []*openapiServer             `json:"servers,omitempty"`
+	Paths      map[string]map[string]*apiOp `json:"paths"`
+	Components *openapiComponents           `json:"components,omitempty"`
+}
+
+type openapiInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openapiServer struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type openapiComponents struct {
+	Schemas map[string]*apiSchema `json:"schemas,omitempty"`
+}
+
+// apiOp is the OpenAPI operation of a route.
+type apiOp struct {
+	OperationID string                  `json:"operationId"`
+	Summary     string                  `json:"summary,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Parameters  []*apiParam             `json:"parameters,omitempty"`
+	RequestBody *apiRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*apiResponse `json:"responses"`
+	Auth        string                  `json:"x-aah-auth,omitempty"`
+}
+
+type apiParam struct {
+	Name     string     `json:"name"`
+	In       string     `json:"in"`
+	Required bool       `json:"required,omitempty"`
+	Schema   *apiSchema `json:"schema"`
+}
+
+type apiRequestBody struct {
+	Required bool                     `json:"required,omitempty"`
+	Content  map[string]*apiMediaType `json:"content"`
+}
+
+type apiMediaType struct {
+	Schema *apiSchema `json:"schema"`
+}
+
+type apiResponse struct {
+	Description string `json:"description"`
+}
+
+// apiSchema is the OpenAPI schema object.
+type apiSchema struct {
+	Ref                  string                `json:"$ref,omitempty"`
+	Type                 string                `json:"type,omitempty"`
+	Format               string                `json:"format,omitempty"`
+	Description          string                `json:"description,omitempty"`
+	Items                *apiSchema            `json:"items,omitempty"`
+	Properties           map[string]*apiSchema `json:"properties,omitempty"`
+	AdditionalProperties *apiSchema            `json:"additionalProperties,omitempty"`
+	Required             []string              `json:"required,omitempty"`
+}
+
+// RefName method returns the component schema name of reference.
+func (s *apiSchema) RefName() string {
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+var apiPrimitiveTypes = map[string]*apiSchema{
+	"string":  {Type: "string"},
+	"bool":    {Type: "boolean"},
+	"int":     {Type: "integer"},
+	"int8":    {Type: "integer", Format: "int32"},
+	"int16":   {Type: "integer", Format: "int32"},
+	"int32":   {Type: "integer", Format: "int32"},
+	"int64":   {Type: "integer", Format: "int64"},
+	"uint":    {Type: "integer"},
+	"uint8":   {Type: "integer", Format: "int32"},
+	"uint16":  {Type: "integer", Format: "int32"},
+	"uint32":  {Type: "integer", Format: "int64"},
+	"uint64":  {Type: "integer", Format: "int64"},
+	"byte":    {Type: "integer", Format: "int32"},
+	"rune":    {Type: "integer", Format: "int32"},
+	"float32": {Type: "number", Format: "float"},
+	"float64": {Type: "number", Format: "double"},
+}
+
+// apiBodyMethods are the HTTP methods, aah binds the struct type action
+// parameter from request body.
+var apiBodyMethods = map[string]bool{"POST": true, "PUT": true, "PATCH": true}
+
+func generateOpenAPIAction(c *cli.Context) error {
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+	cliLog.Infof("Loaded aah project file: %s\n", filepath.Join(aah.AppBaseDir(), aahProjectIdentifier))
+
+	doc, err := buildOpenAPIDoc(projectCfg)
+	if err != nil {
+		logFatal(err)
+	}
+
+	destFile := firstNonEmpty(c.String("o"), c.String("output"))
+	if c.Bool("static") {
+		destFile = filepath.Join(aah.AppBaseDir(), "static", filepath.Base(firstNonEmpty(destFile, "openapi.json")))
+	} else if ess.IsStrEmpty(destFile) {
+		destFile = filepath.Join(aah.AppBaseDir(), "openapi.json")
+	}
+	if destFile, err = filepath.Abs(destFile); err != nil {
+		logFatal(err)
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		logFatal(err)
+	}
+
+	_ = ess.MkDirAll(filepath.Dir(destFile), permRWXRXRX)
+	if err = ioutil.WriteFile(destFile, append(b, '\n'), permRWRWRW); err != nil {
+		logFatalf("Unable to write OpenAPI spec file: %s", err)
+	}
+
+	cliLog.Infof("Generated OpenAPI spec with %d path(s) at '%s'\n", len(doc.Paths), destFile)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// buildOpenAPIDoc method creates the OpenAPI document from application route
+// table, controller action parameters and its doc comments.
+func buildOpenAPIDoc(projectCfg *config.Config) (*openapiDoc, error) {
+	actions, err := inspectAppActions(projectCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := appRouteTable(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &openapiBuilder{
+		src:        &goSource{pkgs: make(map[string]*goPackage)},
+		schemas:    make(map[string]*apiSchema),
+		schemaRefs: make(map[string]string),
+		refHits:    make(map[string]bool),
+	}
+	doc := &openapiDoc{
+		OpenAPI: openapiVersion,
+		Info: &openapiInfo{
+			Title:       aah.AppName(),
+			Description: aah.AppConfig().StringDefault("desc", ""),
+			Version:     firstNonEmpty(getAppVersion(aah.AppBaseDir(), projectCfg), "1.0.0"),
+		},
+		Paths: make(map[string]map[string]*apiOp),
+	}
+
+	hosts := make(map[string]bool)
+	opIDs := make(map[string]bool)
+	for _, r := range routes {
+		if r.Static || r.Method == "WS" {
+			continue
+		}
+
+		m := actions.lookup(r)
+		if m == nil {
+			cliLog.Warnf("Route '%s' => %s.%s is not implemented, skipped from OpenAPI spec", r.Name, r.Controller, r.Action)
+			continue
+		}
+
+		op := b.operation(r, actions.typeImportPath(r))
+		if opIDs[op.OperationID] {
+			op.OperationID = r.Domain + "_" + op.OperationID
+		}
+		opIDs[op.OperationID] = true
+
+		apiPath := openapiPath(r.Path)
+		if _, found := doc.Paths[apiPath]; !found {
+			doc.Paths[apiPath] = make(map[string]*apiOp)
+		}
+		doc.Paths[apiPath][strings.ToLower(r.Method)] = op
+
+		if !hosts[r.Host] {
+			hosts[r.Host] = true
+			doc.Servers = append(doc.Servers, &openapiServer{URL: "//" + r.Host, Description: r.Domain})
+		}
+	}
+
+	if len(b.schemas) > 0 {
+		doc.Components = &openapiComponents{Schemas: b.schemas}
+	}
+	return doc, nil
+}
+
+// typeImportPath method returns the import path of controller or websocket
+// type of given route.
+func (a *appActions) typeImportPath(route *routeInfo) string {
+	types := a.Controllers
+	if route.Method == "WS" {
+		types = a.WebSockets
+	}
+
+	for _, t := range types {
+		if t.Name == route.Controller || strings.HasSuffix(t.ImportPath+"/"+t.Name, "/"+route.Controller) {
+			return t.ImportPath
+		}
+	}
+	return ""
+}
+
+// openapiPath method converts the aah route path parameters (':id', '*filepath')
+// into OpenAPI path template ('{id}', '{filepath}').
+func openapiPath(routePath string) string {
+	segments := strings.Split(routePath, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// routePathParams method returns the path parameter names of aah route path.
+func routePathParams(routePath string) []string {
+	var names []string
+	for _, s := range strings.Split(routePath, "/") {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			names = append(names, s[1:])
+		}
+	}
+	return names
+}
+
+// openapiBuilder builds the operations and component schemas from Go source.
+type openapiBuilder struct {
+	src        *goSource
+	schemas    map[string]*apiSchema
+	schemaRefs map[string]string
+	refHits    map[string]bool
+}
+
+func (b *openapiBuilder) operation(r *routeInfo, controllerImportPath string) *apiOp {
+	op := &apiOp{
+		OperationID: r.Name,
+		Tags:        []string{path.Base(r.Controller)},
+		Responses:   map[string]*apiResponse{"200": {Description: "Successful response"}},
+		Auth:        r.Auth,
+	}
+	if r.Auth == "anonymous" {
+		op.Auth = ""
+	}
+
+	pathParams := make(map[string]bool)
+	for _, name := range routePathParams(r.Path) {
+		pathParams[name] = true
+	}
+
+	pkg := b.src.load(controllerImportPath)
+	var fn *goFuncDecl
+	if pkg != nil {
+		fn = pkg.Methods[path.Base(r.Controller)+"."+r.Action]
+	}
+
+	if fn != nil {
+		op.Summary, op.Description = docSummary(fn.Decl.Doc.Text())
+		for _, field := range fn.Decl.Type.Params.List {
+			for _, name := range field.Names {
+				b.addParam(op, r, name.Name, field.Type, pkg, fn.File, pathParams)
+			}
+		}
+	}
+
+	// path parameters are not captured as action parameter
+	for _, name := range routePathParams(r.Path) {
+		if pathParams[name] {
+			op.Parameters = append(op.Parameters, &apiParam{Name: name, In: "path", Required: true,
+				Schema: &apiSchema{Type: "string"}})
+		}
+	}
+	return op
+}
+
+func (b *openapiBuilder) addParam(op *apiOp, r *routeInfo, name string, expr ast.Expr, pkg *goPackage,
+	file *ast.File, pathParams map[string]bool) {
+	schema := b.schema(expr, pkg, file)
+	if pathParams[name] {
+		delete(pathParams, name)
+		op.Parameters = append(op.Parameters, &apiParam{Name: name, In: "path", Required: true, Schema: schema})
+		return
+	}
+
+	if !ess.IsStrEmpty(schema.Ref) || schema.Type == "object" {
+		if apiBodyMethods[r.Method] {
+			op.RequestBody = &apiRequestBody{
+				Required: true,
+				Content: map[string]*apiMediaType{
+					"application/json": {Schema: schema},
+				},
+			}
+			return
+		}
+
+		// struct parameter of non-body methods is bound from query
+		// parameters, so its fields are listed as query parameters
+		if s := b.schemas[schema.RefName()]; s != nil {
+			var names []string
+			for fname := range s.Properties {
+				names = append(names, fname)
+			}
+			sort.Strings(names)
+			for _, fname := range names {
+				fs := s.Properties[fname]
+				if isStructSchema(fs) || (fs.Type == "array" && isStructSchema(fs.Items)) {
+					// nested struct is not bound from flat query parameters
+					cliLog.Warnf("Route '%s' parameter '%s' field '%s' is nested struct, skipped from query parameters",
+						r.Name, name, fname)
+					continue
+				}
+				op.Parameters = append(op.Parameters, &apiParam{Name: fname, In: "query",
+					Required: inStrSlice(s.Required, fname), Schema: fs})
+			}
+			return
+		}
+	}
+
+	op.Parameters = append(op.Parameters, &apiParam{Name: name, In: "query", Schema: schema})
+}
+
+// isStructSchema method reports whether schema is struct i.e. reference or
+// object without additional properties.
+func isStructSchema(s *apiSchema) bool {
+	return s != nil && (!ess.IsStrEmpty(s.Ref) || (s.Type == "object" && s.AdditionalProperties == nil))
+}
+
+// schema method returns the OpenAPI schema of Go type expression, named
+// struct types are added into components and referred.
+func (b *openapiBuilder) schema(expr ast.Expr, pkg *goPackage, file *ast.File) *apiSchema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if s, found := apiPrimitiveTypes[t.Name]; found {
+			return &apiSchema{Type: s.Type, Format: s.Format}
+		}
+		return b.namedSchema(pkg, t.Name)
+	case *ast.StarExpr:
+		return b.schema(t.X, pkg, file)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &apiSchema{Type: "string", Format: "byte"}
+		}
+		return &apiSchema{Type: "array", Items: b.schema(t.Elt, pkg, file)}
+	case *ast.MapType:
+		return &apiSchema{Type: "object", AdditionalProperties: b.schema(t.Value, pkg, file)}
+	case *ast.StructType:
+		return b.structSchema(t, pkg, file)
+	case *ast.SelectorExpr:
+		pkgIdent, ok := t.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		importPath := b.src.resolveImport(file, pkgIdent.Name)
+		switch importPath + "." + t.Sel.Name {
+		case "time.Time":
+			return &apiSchema{Type: "string", Format: "date-time"}
+		case "time.Duration":
+			return &apiSchema{Type: "integer", Format: "int64"}
+		}
+		return b.namedSchema(b.src.load(importPath), t.Sel.Name)
+	}
+	return &apiSchema{}
+}
+
+func (b *openapiBuilder) namedSchema(pkg *goPackage, name string) *apiSchema {
+	if pkg == nil {
+		return &apiSchema{Type: "object"}
+	}
+
+	decl, found := pkg.Types[name]
+	if !found {
+		return &apiSchema{Type: "object"}
+	}
+
+	key := pkg.ImportPath + "." + name
+	if ref, found := b.schemaRefs[key]; found {
+		b.refHits[ref] = true
+		return &apiSchema{Ref: "#/components/schemas/" + ref}
+	}
+
+	// reference is registered before recursing into the type, so self
+	// referential types (e.g. 'type Tree map[string]Tree') are resolved
+	taken := make(map[string]bool, len(b.schemas))
+	for k := range b.schemas {
+		taken[k] = true
+	}
+	ref := name
+	if taken[ref] {
+		ref = uniqueIdent(pkg.Name+name, taken)
+	}
+	b.schemaRefs[key] = ref
+	b.schemas[ref] = &apiSchema{} // placeholder for recursive types
+
+	var s *apiSchema
+	st, isStruct := decl.Spec.Type.(*ast.StructType)
+	if isStruct {
+		s = b.structSchema(st, pkg, decl.File)
+	} else {
+		s = b.schema(decl.Spec.Type, pkg, decl.File)
+	}
+	if ess.IsStrEmpty(s.Ref) {
+		s.Description, _ = docSummary(decl.Doc)
+	}
+
+	// non-struct named type is inlined, unless it is referred while resolving
+	// itself i.e. recursive type
+	if !isStruct && !b.refHits[ref] {
+		delete(b.schemaRefs, key)
+		delete(b.schemas, ref)
+		return s
+	}
+
+	b.schemas[ref] = s
+	return &apiSchema{Ref: "#/components/schemas/" + ref}
+}
+
+func (b *openapiBuilder) structSchema(st *ast.StructType, pkg *goPackage, file *ast.File) *apiSchema {
+	s := &apiSchema{Type: "object", Properties: make(map[string]*apiSchema)}
+	b.addStructFields(s, st, pkg, file)
+	return s
+}
+
+func (b *openapiBuilder) addStructFields(s *apiSchema, st *ast.StructType, pkg *goPackage, file *ast.File) {
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if v, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(v)
+			}
+		}
+
+		jsonName := strings.Split(tag.Get("json"), ",")[0]
+		if jsonName == "-" {
+			continue
+		}
+		required := strings.Contains(tag.Get("validate"), "required")
+
+		// embedded struct fields are promoted
+		if len(field.Names) == 0 {
+			if ess.IsStrEmpty(jsonName) {
+				if est, epkg, efile := b.src.structDecl(field.Type, pkg, file); est != nil {
+					b.addStructFields(s, est, epkg, efile)
+				}
+				continue
+			}
+		}
+
+		names := []string{jsonName}
+		if ess.IsStrEmpty(jsonName) {
+			names = names[:0]
+			for _, n := range field.Names {
+				names = append(names, n.Name)
+			}
+		}
+
+		for i, name := range names {
+			if len(field.Names) > 0 && !isExported(field.Names[i].Name) {
+				continue
+			}
+			fs := b.schema(field.Type, pkg, file)
+			if ess.IsStrEmpty(fs.Ref) {
+				fs.Description, _ = docSummary(field.Doc.Text() + field.Comment.Text())
+			}
+			s.Properties[name] = fs
+			if required {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+}
+
+// docSummary method returns the first line of doc comment as summary and
+// complete doc comment as description.
+func docSummary(doc string) (string, string) {
+	doc = strings.TrimSpace(doc)
+	if ess.IsStrEmpty(doc) {
+		return "", ""
+	}
+	summary := strings.TrimSpace(strings.SplitN(doc, "\n", 2)[0])
+	if summary == doc {
+		return summary, ""
+	}
+	return summary, doc
+}
+
+func inStrSlice(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Go source
+//___________________________________
+
+// goSource parses and caches the Go packages by import path for type
+// declarations and method doc comments.
+type goSource struct {
+	pkgs map[string]*goPackage
+}
+
+type goPackage struct {
+	Name       string
+	ImportPath string
+	Types      map[string]*goTypeDecl
+	Methods    map[string]*goFuncDecl
+}
+
+type goTypeDecl struct {
+	Spec *ast.TypeSpec
+	Doc  string
+	File *ast.File
+}
+
+type goFuncDecl struct {
+	Decl *ast.FuncDecl
+	File *ast.File
+}
+
+// load method parses the Go package of given import path, application
+// vendor directory takes precedence over GOPATH. It returns nil if package
+// not found.
+func (gs *goSource) load(importPath string) *goPackage {
+	if ess.IsStrEmpty(importPath) {
+		return nil
+	}
+	if pkg, found := gs.pkgs[importPath]; found {
+		return pkg
+	}
+	gs.pkgs[importPath] = nil
+
+	dir := filepath.Join(aah.AppBaseDir(), "vendor", filepath.FromSlash(importPath))
+	if !ess.IsFileExists(dir) {
+		dir = filepath.Join(gosrcDir, filepath.FromSlash(importPath))
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		cliLog.Debugf("Unable to parse package '%s': %s", importPath, err)
+		return nil
+	}
+
+	for name, p := range pkgs {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+
+		pkg := &goPackage{
+			Name:       name,
+			ImportPath: importPath,
+			Types:      make(map[string]*goTypeDecl),
+			Methods:    make(map[string]*goFuncDecl),
+		}
+		for _, f := range p.Files {
+			collectGoDecls(pkg, f)
+		}
+		gs.pkgs[importPath] = pkg
+		return pkg
+	}
+	return nil
+}
+
+// resolveImport method returns the import path of package identifier used
+// in the given file.
+func (gs *goSource) resolveImport(file *ast.File, pkgIdent string) string {
+	if file == nil {
+		return ""
+	}
+
+	for _, imp := range file.Imports {
+		importPath, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil {
+			if imp.Name.Name == pkgIdent {
+				return importPath
+			}
+			continue
+		}
+
+		if importPath == pkgIdent || path.Base(importPath) == pkgIdent {
+			return importPath
+		}
+		if pkg := gs.load(importPath); pkg != nil && pkg.Name == pkgIdent {
+			return importPath
+		}
+	}
+	return ""
+}
+
+// structDecl method resolves the struct type declaration of type
+// expression.
+func (gs *goSource) structDecl(expr ast.Expr, pkg *goPackage, file *ast.File) (*ast.StructType, *goPackage, *ast.File) {
+	var name string
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return gs.structDecl(t.X, pkg, file)
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		if pkgIdent, ok := t.X.(*ast.Ident); ok {
+			pkg, name = gs.load(gs.resolveImport(file, pkgIdent.Name)), t.Sel.Name
+		}
+	}
+
+	if pkg == nil {
+		return nil, nil, nil
+	}
+	if decl, found := pkg.Types[name]; found {
+		if st, ok := decl.Spec.Type.(*ast.StructType); ok {
+			return st, pkg, decl.File
+		}
+	}
+	return nil, nil, nil
+}
+
+func collectGoDecls(pkg *goPackage, f *ast.File) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				doc := ts.Doc.Text()
+				if ess.IsStrEmpty(doc) && len(d.Specs) == 1 {
+					doc = d.Doc.Text()
+				}
+				pkg.Types[ts.Name.Name] = &goTypeDecl{Spec: ts, Doc: doc, File: f}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				continue
+			}
+
+			recv := d.Recv.List[0].Type
+			if se, ok := recv.(*ast.StarExpr); ok {
+				recv = se.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				pkg.Methods[ident.Name+"."+d.Name.Name] = &goFuncDecl{Decl: d, File: f}
+			}
+		}
+	}
+}
@@ -0,0 +1,535 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+type (
+	// resourceModel holds the model info of 'generate resource'.
+	resourceModel struct {
+		Type      string
+		Var       string
+		Plural    string
+		PluralVar string
+		File      string
+		Fields    []*resourceField
+	}
+
+	// resourceField holds the model field info.
+	resourceField struct {
+		Name  string
+		Key   string
+		Label string
+		Type  string
+		Input string
+	}
+)
+
+// resourceFieldTypes is the supported field types and its Go type and HTML
+// input type.
+var resourceFieldTypes = map[string][2]string{
+	"string":  {"string", "text"},
+	"text":    {"string", "textarea"},
+	"int":     {"int", "number"},
+	"int64":   {"int64", "number"},
+	"float":   {"float64", "number"},
+	"float64": {"float64", "number"},
+	"bool":    {"bool", "checkbox"},
+}
+
+func generateResourceAction(c *cli.Context) error {
+	if c.NArg() == 0 {
+		_ = cli.ShowSubcommandHelp(c)
+		return nil
+	}
+
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+
+	app := resourceAppTmplData(aah.AppConfig(), !c.Bool("api"))
+	actions := []string{"index", "show", "create", "update", "destroy"}
+	if app.IsWebApp() {
+		actions = []string{"index", "show", "new", "create", "edit", "update", "destroy"}
+	}
+	ctrl, err := newScaffoldController(c.Args().First(), actions)
+	if err != nil {
+		logFatal(err)
+	}
+	model, err := newResourceModel(strings.TrimSuffix(ctrl.Type, "Controller"), c.Args().Tail())
+	if err != nil {
+		logFatal(err)
+	}
+
+	data := map[string]interface{}{
+		"App":          app,
+		"Ctrl":         ctrl,
+		"Model":        model,
+		"ModelsImport": path.Join(aah.AppImportPath(), "app", "models"),
+	}
+
+	// model and in-memory repository
+	modelFile := filepath.Join(aah.AppBaseDir(), "app", "models", model.File)
+	if checkAndConfirmOverwrite(c, modelFile) {
+		return nil
+	}
+	if err = generateScaffoldSource(modelFile, aahResourceModelTemplate, data); err != nil {
+		logFatal(err)
+	}
+	cliLog.Infof("Generated model '%s' and repository at %s", model.Type, stripGoSrcPath(modelFile))
+
+	// controller
+	destFile := filepath.Join(aah.AppBaseDir(), "app", "controllers", filepath.FromSlash(ctrl.ImportDir), ctrl.File)
+	if checkAndConfirmOverwrite(c, destFile) {
+		return nil
+	}
+	if err = generateScaffoldSource(destFile, aahResourceControllerTemplate, data); err != nil {
+		logFatal(err)
+	}
+	cliLog.Infof("Generated controller '%s' at %s", ctrl.Ref, stripGoSrcPath(destFile))
+
+	// views for web application
+	if app.IsWebApp() {
+		if app.ViewEngine == "go" {
+			generateResourceViews(app, ctrl, data)
+		} else {
+			cliLog.Warnf("View engine '%s' is not supported by resource generator, views are not created", app.ViewEngine)
+		}
+	}
+
+	// routes.conf wiring
+	routesFile := filepath.Join(aah.AppBaseDir(), "config", "routes.conf")
+	domain := firstNonEmpty(c.String("d"), c.String("domain"))
+	if err = addRoutes(routesFile, domain, ctrl.routeEntries()); err != nil {
+		logFatalf("Unable to add routes into 'config/routes.conf': %s", err)
+	}
+
+	verifyScaffold(projectCfg, "controllers", aah.AppRouter().RegisteredActions(), libImportPath("aah"), ctrl)
+
+	cliLog.Infof("Swap the in-memory repository with your data store by assigning 'models.%s' in 'app/init.go'", model.Plural)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// resourceAppTmplData method returns the application template data of
+// existing application from 'aah.conf', application is web if it has
+// 'views' directory.
+func resourceAppTmplData(appCfg *config.Config, allowWeb bool) *appTmplData {
+	app := &appTmplData{
+		Type:           typeAPI,
+		ViewEngine:     appCfg.StringDefault("view.engine", "go"),
+		ViewFileExt:    appCfg.StringDefault("view.ext", ".html"),
+		TmplDelimLeft:  "{{",
+		TmplDelimRight: "}}",
+	}
+	if allowWeb && ess.IsFileExists(filepath.Join(aah.AppBaseDir(), "views")) {
+		app.Type = typeWeb
+	}
+	if delims := strings.Split(appCfg.StringDefault("view.delimiters", "{{.}}"), "."); len(delims) == 2 {
+		app.TmplDelimLeft, app.TmplDelimRight = delims[0], delims[1]
+	}
+	return app
+}
+
+// newResourceModel method creates the model info from type name and field
+// definitions e.g. 'name:string', 'price:float'. Field type defaults to
+// 'string'.
+func newResourceModel(typeName string, defs []string) (*resourceModel, error) {
+	plural := camelIdent(pluralize(snakeCase(typeName)), true)
+	model := &resourceModel{
+		Type:      typeName,
+		Var:       camelIdent(typeName, false),
+		Plural:    plural,
+		PluralVar: camelIdent(plural, false),
+		File:      snakeCase(typeName) + ".go",
+	}
+
+	seen := map[string]bool{"id": true}
+	for _, def := range defs {
+		parts := strings.SplitN(strings.TrimSpace(def), ":", 2)
+		if len(parts) == 1 {
+			parts = append(parts, "string")
+		}
+		ft, found := resourceFieldTypes[strings.ToLower(parts[1])]
+		if !found {
+			return nil, fmt.Errorf("field '%s' has unsupported type '%s', try one of these "+
+				"'string', 'text', 'int', 'int64', 'float', 'float64', 'bool'", parts[0], parts[1])
+		}
+
+		key := snakeCase(camelIdent(parts[0], true))
+		if ess.IsStrEmpty(parts[0]) || seen[key] {
+			return nil, fmt.Errorf("field '%s' is empty or duplicate", def)
+		}
+		seen[key] = true
+
+		model.Fields = append(model.Fields, &resourceField{
+			Name:  camelIdent(parts[0], true),
+			Key:   key,
+			Label: strings.Title(strings.Replace(key, "_", " ", -1)),
+			Type:  ft[0],
+			Input: ft[1],
+		})
+	}
+	return model, nil
+}
+
+// generateResourceViews method creates the index, show, new and edit views
+// in 'views/pages/<controller>' with application template delimiters,
+// existing view is not touched.
+func generateResourceViews(app *appTmplData, ctrl *scaffoldController, data map[string]interface{}) {
+	views := map[string]string{
+		"index": aahResourceIndexViewTemplate,
+		"show":  aahResourceShowViewTemplate,
+		"new":   aahResourceFormViewTemplate,
+		"edit":  aahResourceFormViewTemplate,
+	}
+	for _, name := range []string{"index", "show", "new", "edit"} {
+		viewFile := filepath.Join(aah.AppBaseDir(), "views", "pages", filepath.FromSlash(ctrl.ViewDir), name+app.ViewFileExt)
+		if ess.IsFileExists(viewFile) {
+			cliLog.Infof("View %s already exists, skipped", stripGoSrcPath(viewFile))
+			continue
+		}
+
+		data["View"] = name
+		buf := &bytes.Buffer{}
+		if err := renderTmpl(buf, views[name], data); err != nil {
+			logFatal(err)
+		}
+		if err := ess.MkDirAll(filepath.Dir(viewFile), permRWXRXRX); err != nil {
+			logFatal(err)
+		}
+		if err := ioutil.WriteFile(viewFile, buf.Bytes(), permRWRWRW); err != nil {
+			logFatal(err)
+		}
+		cliLog.Infof("Generated view %s", stripGoSrcPath(viewFile))
+	}
+}
+
+// Columns method returns the column count of index view table, ID, fields
+// and links.
+func (m *resourceModel) Columns() int {
+	return len(m.Fields) + 2
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Resource Templates
+//___________________________________
+
+const aahResourceModelTemplate = `// Generated by 'aah generate resource', feel free to customize it.
+
+package models
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+{{ $m := .Model }}
+// Err{{ $m.Type }}NotFound is returned by {{ $m.Type }}Repository when {{ $m.Var }} does not exists.
+var Err{{ $m.Type }}NotFound = errors.New("{{ $m.Var }} not found")
+
+// {{ $m.Plural }} is the {{ $m.Type }}Repository used by controller, assign your
+// implementation e.g. database backed repository in 'app/init.go'.
+var {{ $m.Plural }} {{ $m.Type }}Repository = New{{ $m.Type }}MemoryRepository()
+
+// {{ $m.Type }} is the resource model.
+type {{ $m.Type }} struct {
+	ID int64 ` + "`" + `json:"id"` + "`" + `
+{{- range $m.Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `json:"{{ .Key }}" bind:"{{ .Key }}"` + "`" + `
+{{- end }}
+}
+
+// {{ $m.Type }}Repository is the data store of {{ $m.Type }}.
+type {{ $m.Type }}Repository interface {
+	All() ([]*{{ $m.Type }}, error)
+	Get(id int64) (*{{ $m.Type }}, error)
+	Create({{ $m.Var }} *{{ $m.Type }}) error
+	Update({{ $m.Var }} *{{ $m.Type }}) error
+	Delete(id int64) error
+}
+
+// New{{ $m.Type }}MemoryRepository method returns the in-memory {{ $m.Type }}Repository,
+// data is lost on application restart.
+func New{{ $m.Type }}MemoryRepository() {{ $m.Type }}Repository {
+	return &{{ $m.Var }}MemoryRepository{items: make(map[int64]*{{ $m.Type }})}
+}
+
+type {{ $m.Var }}MemoryRepository struct {
+	mu    sync.RWMutex
+	seq   int64
+	items map[int64]*{{ $m.Type }}
+}
+
+func (r *{{ $m.Var }}MemoryRepository) All() ([]*{{ $m.Type }}, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	{{ $m.PluralVar }} := make([]*{{ $m.Type }}, 0, len(r.items))
+	for _, v := range r.items {
+		c := *v
+		{{ $m.PluralVar }} = append({{ $m.PluralVar }}, &c)
+	}
+	sort.Slice({{ $m.PluralVar }}, func(i, j int) bool { return {{ $m.PluralVar }}[i].ID < {{ $m.PluralVar }}[j].ID })
+	return {{ $m.PluralVar }}, nil
+}
+
+func (r *{{ $m.Var }}MemoryRepository) Get(id int64) (*{{ $m.Type }}, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, found := r.items[id]
+	if !found {
+		return nil, Err{{ $m.Type }}NotFound
+	}
+	c := *v
+	return &c, nil
+}
+
+func (r *{{ $m.Var }}MemoryRepository) Create({{ $m.Var }} *{{ $m.Type }}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	{{ $m.Var }}.ID = r.seq
+	c := *{{ $m.Var }}
+	r.items[c.ID] = &c
+	return nil
+}
+
+func (r *{{ $m.Var }}MemoryRepository) Update({{ $m.Var }} *{{ $m.Type }}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.items[{{ $m.Var }}.ID]; !found {
+		return Err{{ $m.Type }}NotFound
+	}
+	c := *{{ $m.Var }}
+	r.items[c.ID] = &c
+	return nil
+}
+
+func (r *{{ $m.Var }}MemoryRepository) Delete(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.items[id]; !found {
+		return Err{{ $m.Type }}NotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+`
+
+const aahResourceControllerTemplate = `// Generated by 'aah generate resource', feel free to customize it.
+
+package {{ .Ctrl.Package }}
+
+import (
+	{{ if .App.IsWebApp }}"net/http"
+
+	{{ end }}"aahframework.org/aah.v0"
+	"{{ .ModelsImport }}"
+)
+{{ $c := .Ctrl }}{{ $m := .Model }}{{ $web := .App.IsWebApp }}
+// {{ $c.Type }} handles the '{{ $c.Path }}' resource routes.
+type {{ $c.Type }} struct {
+	*aah.Context
+}
+
+// Index method handles 'GET {{ $c.Path }}'.
+func (c *{{ $c.Type }}) Index() {
+	{{ $m.PluralVar }}, err := models.{{ $m.Plural }}.All()
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	{{ if $web }}c.Reply().HTML(aah.Data{"{{ $m.PluralVar }}": {{ $m.PluralVar }}}){{ else }}c.Reply().Ok().JSON({{ $m.PluralVar }}){{ end }}
+}
+
+// Show method handles 'GET {{ $c.Path }}/:id'.
+func (c *{{ $c.Type }}) Show(id int64) {
+	{{ $m.Var }}, err := models.{{ $m.Plural }}.Get(id)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	{{ if $web }}c.Reply().HTML(aah.Data{"{{ $m.Var }}": {{ $m.Var }}}){{ else }}c.Reply().Ok().JSON({{ $m.Var }}){{ end }}
+}
+{{ if $web }}
+// New method handles 'GET {{ $c.Path }}/new'.
+func (c *{{ $c.Type }}) New() {
+	c.Reply().HTML(aah.Data{"{{ $m.Var }}": &models.{{ $m.Type }}{}})
+}
+{{ end }}
+// Create method handles 'POST {{ $c.Path }}'.
+func (c *{{ $c.Type }}) Create({{ $m.Var }} *models.{{ $m.Type }}) {
+	if err := models.{{ $m.Plural }}.Create({{ $m.Var }}); err != nil {
+		c.replyError(err)
+		return
+	}
+	{{ if $web }}c.Reply().Redirect(c.RouteURL("{{ $c.RoutePrefix }}_show", {{ $m.Var }}.ID)){{ else }}c.Reply().Created().JSON({{ $m.Var }}){{ end }}
+}
+{{ if $web }}
+// Edit method handles 'GET {{ $c.Path }}/:id/edit'.
+func (c *{{ $c.Type }}) Edit(id int64) {
+	{{ $m.Var }}, err := models.{{ $m.Plural }}.Get(id)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.Reply().HTML(aah.Data{"{{ $m.Var }}": {{ $m.Var }}})
+}
+{{ end }}
+// Update method handles 'PUT {{ $c.Path }}/:id'.
+func (c *{{ $c.Type }}) Update(id int64, {{ $m.Var }} *models.{{ $m.Type }}) {
+	{{ $m.Var }}.ID = id
+	if err := models.{{ $m.Plural }}.Update({{ $m.Var }}); err != nil {
+		c.replyError(err)
+		return
+	}
+	{{ if $web }}c.Reply().RedirectWithStatus(c.RouteURL("{{ $c.RoutePrefix }}_show", id), http.StatusSeeOther){{ else }}c.Reply().Ok().JSON({{ $m.Var }}){{ end }}
+}
+
+// Destroy method handles 'DELETE {{ $c.Path }}/:id'.
+func (c *{{ $c.Type }}) Destroy(id int64) {
+	if err := models.{{ $m.Plural }}.Delete(id); err != nil {
+		c.replyError(err)
+		return
+	}
+	{{ if $web }}c.Reply().RedirectWithStatus(c.RouteURL("{{ $c.RoutePrefix }}_index"), http.StatusSeeOther){{ else }}c.Reply().NoContent(){{ end }}
+}
+
+// replyError method replies the repository error, not found as 404.
+func (c *{{ $c.Type }}) replyError(err error) {
+	if err == models.Err{{ $m.Type }}NotFound {
+		c.Reply().NotFound().{{ if $web }}Text("%s", err){{ else }}JSON(aah.Data{"message": err.Error()}){{ end }}
+		return
+	}
+	c.Log().Error(err)
+	c.Reply().InternalServerError().{{ if $web }}Text("%s", err){{ else }}JSON(aah.Data{"message": err.Error()}){{ end }}
+}
+`
+
+// aahResourceMethodScript submits the form having 'data-method' attribute
+// with its HTTP method e.g. PUT, DELETE, since HTML form supports only GET
+// and POST.
+const aahResourceMethodScript = `
+<script>
+  document.querySelectorAll('form[data-method]').forEach(function (form) {
+    form.addEventListener('submit', function (e) {
+      e.preventDefault();
+      fetch(form.action, {
+        method: form.getAttribute('data-method'),
+        body: new URLSearchParams(new FormData(form)),
+        credentials: 'same-origin'
+      }).then(function (res) { window.location = res.url; });
+    });
+  });
+</script>`
+
+const aahResourceIndexViewTemplate = `{{ $L := .App.TmplDelimLeft }}{{ $R := .App.TmplDelimRight }}{{ $p := .Ctrl.RoutePrefix -}}
+{{ $L }} define "title" {{ $R }}<title>{{ .Model.Plural }}</title>{{ $L }} end {{ $R }}
+
+{{ $L }} define "body" {{ $R }}
+<h1>{{ .Model.Plural }}</h1>
+<p><a href="{{ $L }} rurl . "{{ $p }}_new" {{ $R }}">New {{ .Model.Type }}</a></p>
+<table>
+  <thead>
+    <tr>
+      <th>ID</th>
+      {{- range .Model.Fields }}
+      <th>{{ .Label }}</th>
+      {{- end }}
+      <th></th>
+    </tr>
+  </thead>
+  <tbody>
+    {{ $L }} range .{{ .Model.PluralVar }} {{ $R }}
+    <tr>
+      <td>{{ $L }} .ID {{ $R }}</td>
+      {{- range .Model.Fields }}
+      <td>{{ $L }} .{{ .Name }} {{ $R }}</td>
+      {{- end }}
+      <td>
+        <a href="{{ $L }} rurl $ "{{ $p }}_show" .ID {{ $R }}">Show</a>
+        <a href="{{ $L }} rurl $ "{{ $p }}_edit" .ID {{ $R }}">Edit</a>
+      </td>
+    </tr>
+    {{ $L }} else {{ $R }}
+    <tr>
+      <td colspan="{{ .Model.Columns }}">No {{ .Model.PluralVar }} yet.</td>
+    </tr>
+    {{ $L }} end {{ $R }}
+  </tbody>
+</table>
+{{ $L }} end {{ $R }}
+`
+
+const aahResourceShowViewTemplate = `{{ $L := .App.TmplDelimLeft }}{{ $R := .App.TmplDelimRight }}{{ $p := .Ctrl.RoutePrefix }}{{ $v := .Model.Var -}}
+{{ $L }} define "title" {{ $R }}<title>{{ .Model.Type }}</title>{{ $L }} end {{ $R }}
+
+{{ $L }} define "body" {{ $R }}
+<h1>{{ .Model.Type }} #{{ $L }} .{{ $v }}.ID {{ $R }}</h1>
+<dl>
+  {{- range .Model.Fields }}
+  <dt>{{ .Label }}</dt>
+  <dd>{{ $L }} .{{ $v }}.{{ .Name }} {{ $R }}</dd>
+  {{- end }}
+</dl>
+<form method="POST" data-method="DELETE" action="{{ $L }} rurl . "{{ $p }}_destroy" .{{ $v }}.ID {{ $R }}">
+  <input type="hidden" name="anti_csrf_token" value="{{ $L }} anticsrftoken . {{ $R }}">
+  <a href="{{ $L }} rurl . "{{ $p }}_edit" .{{ $v }}.ID {{ $R }}">Edit</a>
+  <a href="{{ $L }} rurl . "{{ $p }}_index" {{ $R }}">Back</a>
+  <button type="submit">Delete</button>
+</form>
+` + aahResourceMethodScript + `
+{{ $L }} end {{ $R }}
+`
+
+const aahResourceFormViewTemplate = `{{ $L := .App.TmplDelimLeft }}{{ $R := .App.TmplDelimRight }}{{ $p := .Ctrl.RoutePrefix }}{{ $v := .Model.Var }}{{ $edit := eq .View "edit" -}}
+{{ $L }} define "title" {{ $R }}<title>{{ if $edit }}Edit{{ else }}New{{ end }} {{ .Model.Type }}</title>{{ $L }} end {{ $R }}
+
+{{ $L }} define "body" {{ $R }}
+<h1>{{ if $edit }}Edit{{ else }}New{{ end }} {{ .Model.Type }}</h1>
+{{ if $edit -}}
+<form method="POST" data-method="PUT" action="{{ $L }} rurl . "{{ $p }}_update" .{{ $v }}.ID {{ $R }}">
+{{- else -}}
+<form method="POST" action="{{ $L }} rurl . "{{ $p }}_create" {{ $R }}">
+{{- end }}
+  <input type="hidden" name="anti_csrf_token" value="{{ $L }} anticsrftoken . {{ $R }}">
+  {{- range .Model.Fields }}
+  <p>
+    <label for="{{ .Key }}">{{ .Label }}</label>
+    {{- if eq .Input "textarea" }}
+    <textarea id="{{ .Key }}" name="{{ .Key }}">{{ $L }} .{{ $v }}.{{ .Name }} {{ $R }}</textarea>
+    {{- else if eq .Input "checkbox" }}
+    <input type="checkbox" id="{{ .Key }}" name="{{ .Key }}" value="true"{{ $L }} if .{{ $v }}.{{ .Name }} {{ $R }} checked{{ $L }} end {{ $R }}>
+    {{- else }}
+    <input type="{{ .Input }}" id="{{ .Key }}" name="{{ .Key }}"{{ if eq .Type "float64" }} step="any"{{ end }} value="{{ $L }} .{{ $v }}.{{ .Name }} {{ $R }}">
+    {{- end }}
+  </p>
+  {{- end }}
+  <button type="submit">Save</button>
+  <a href="{{ $L }} rurl . "{{ $p }}_index" {{ $R }}">Back</a>
+</form>
+{{- if $edit }}
+` + aahResourceMethodScript + `{{ end }}
+{{ $L }} end {{ $R }}
+`
@@ -0,0 +1,347 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/ainsp.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/router.v0"
+)
+
+var routesCmd = cli.Command{
+	Name:    "routes",
+	Aliases: []string{"rt"},
+	Usage:   "Prints the resolved route table of aah application",
+	Description: `Command routes prints every route of aah application with domain, method,
+	path pattern, route name, controller, action, action parameters and auth scheme.
+	Actions configured in 'routes.conf', however not implemented in controller are flagged.
+
+	Path and name filters supports wildcard pattern, '*' matches any characters including '/'
+	(e.g. '/api/*' matches '/api/v1/users') and '?' matches single character, otherwise
+	substring match.
+
+	Examples of short and long flags:
+		aah routes
+		aah routes -e prod
+		aah routes -i github.com/user/appname --json
+		aah routes --path /api/ --name "user*"`,
+	Action: routesAction,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "i, importpath",
+			Usage: "Import path of aah application",
+		},
+		cli.StringFlag{
+			Name:  "e, envprofile",
+			Usage: "Environment profile name to activate. For e.g: dev, qa, prod, etc",
+		},
+		cli.StringFlag{
+			Name:  "p, path",
+			Usage: "Filters the routes by path pattern",
+		},
+		cli.StringFlag{
+			Name:  "n, name",
+			Usage: "Filters the routes by route name",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "Prints the route table in JSON format",
+		},
+	},
+}
+
+// routeInfo holds the resolved route details for route table.
+type routeInfo struct {
+	Domain      string       `json:"domain"`
+	Host        string       `json:"host"`
+	Method      string       `json:"method"`
+	Path        string       `json:"path"`
+	Name        string       `json:"name"`
+	Controller  string       `json:"controller,omitempty"`
+	Action      string       `json:"action,omitempty"`
+	Parameters  []*paramInfo `json:"parameters,omitempty"`
+	Auth        string       `json:"auth,omitempty"`
+	Static      bool         `json:"static,omitempty"`
+	Implemented bool         `json:"implemented"`
+}
+
+type paramInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func routesAction(c *cli.Context) error {
+	importPath := appImportPath(c)
+
+	envProfile := firstNonEmpty(c.String("e"), c.String("envprofile"))
+	if !ess.IsStrEmpty(envProfile) {
+		aah.OnInit(func(_ *aah.Event) {
+			aah.AppConfig().SetString("env.active", envProfile)
+		})
+	}
+
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+
+	actions, err := inspectAppActions(projectCfg)
+	if err != nil {
+		logFatal(err)
+	}
+
+	routes, err := appRouteTable(actions)
+	if err != nil {
+		logFatal(err)
+	}
+
+	pathFilter := firstNonEmpty(c.String("p"), c.String("path"))
+	nameFilter := firstNonEmpty(c.String("n"), c.String("name"))
+	var filtered []*routeInfo
+	for _, r := range routes {
+		if matchFilter(pathFilter, r.Path) && matchFilter(nameFilter, r.Name) {
+			filtered = append(filtered, r)
+		}
+	}
+
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(filtered)
+	}
+
+	printRouteTable(filtered)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// appActions holds the controllers and websockets of application with its
+// methods keyed by controller name.
+type appActions struct {
+	Controllers []*ainsp.TypeInfo
+	WebSockets  []*ainsp.TypeInfo
+	methods     map[string]map[string]*ainsp.MethodInfo
+	wsMethods   map[string]map[string]*ainsp.MethodInfo
+}
+
+// lookup method returns the action method of given route, nil if not found.
+func (a *appActions) lookup(route *routeInfo) *ainsp.MethodInfo {
+	if route.Method == "WS" {
+		return lookupActionMethod(a.wsMethods, route.Controller, route.Action)
+	}
+	return lookupActionMethod(a.methods, route.Controller, route.Action)
+}
+
+// inspectAppActions method does Go AST processing of application controllers
+// and websockets.
+func inspectAppActions(projectCfg *config.Config) (*appActions, error) {
+	excludes, _ := projectCfg.StringList("build.ast_excludes")
+	appCodeDir := filepath.Join(aah.AppBaseDir(), "app")
+	acntlr, err := inspectActions(filepath.Join(appCodeDir, "controllers"),
+		ess.Excludes(excludes), aah.AppRouter().RegisteredActions())
+	if err != nil {
+		return nil, err
+	}
+
+	wsc, err := inspectActions(filepath.Join(appCodeDir, "websockets"),
+		ess.Excludes(excludes), aah.AppRouter().RegisteredWSActions())
+	if err != nil {
+		return nil, err
+	}
+
+	a := &appActions{
+		Controllers: acntlr.FindTypeByEmbeddedType(fmt.Sprintf("%s.Context", libImportPath("aah"))),
+		WebSockets:  wsc.FindTypeByEmbeddedType(fmt.Sprintf("%s.Context", libImportPath("ws"))),
+	}
+	a.methods = actionMethods(a.Controllers)
+	a.wsMethods = actionMethods(a.WebSockets)
+	return a, nil
+}
+
+// appRouteTable method resolves all the routes of application domains. Route
+// names are read from 'routes.conf' and resolved via application router.
+func appRouteTable(actions *appActions) ([]*routeInfo, error) {
+	routesCfg, err := config.LoadFile(filepath.Join(aah.AppBaseDir(), "config", "routes.conf"))
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []*routeInfo
+	for _, domain := range aah.AppRouter().Domains {
+		keyPath := "domains." + domain.Key
+		var names []string
+		collectRouteNames(routesCfg, keyPath+".routes", &names)
+		names = append(names, routesCfg.KeysByPath(keyPath+".static")...)
+
+		for _, name := range names {
+			route := domain.LookupByName(name)
+			if route == nil {
+				continue
+			}
+
+			ri := &routeInfo{
+				Domain:      domain.Key,
+				Host:        domain.Host,
+				Method:      route.Method,
+				Path:        route.Path,
+				Name:        route.Name,
+				Auth:        firstNonEmpty(route.Auth, domain.DefaultAuth),
+				Static:      route.IsStatic,
+				Implemented: true,
+			}
+			if route.IsStatic {
+				ri.Method = "GET"
+				routes = append(routes, ri)
+				continue
+			}
+
+			ri.Controller, ri.Action = route.Controller, route.Action
+			if m := actions.lookup(ri); m != nil {
+				for _, p := range m.Parameters {
+					ri.Parameters = append(ri.Parameters, &paramInfo{Name: p.Name, Type: paramTypeName(p)})
+				}
+			} else {
+				ri.Implemented = router.IsDefaultAction(route.Action)
+			}
+			routes = append(routes, ri)
+		}
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Domain != routes[j].Domain {
+			return routes[i].Domain < routes[j].Domain
+		}
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes, nil
+}
+
+// collectRouteNames method collects the route names recursively including
+// child routes.
+func collectRouteNames(routesCfg *config.Config, keyPath string, names *[]string) {
+	for _, name := range routesCfg.KeysByPath(keyPath) {
+		*names = append(*names, name)
+		collectRouteNames(routesCfg, keyPath+"."+name+".routes", names)
+	}
+}
+
+// actionMethods method returns the methods of given types keyed by
+// controller name and also by namespaced controller name
+// (e.g. 'admin/DashboardController').
+func actionMethods(types []*ainsp.TypeInfo) map[string]map[string]*ainsp.MethodInfo {
+	controllersPrefix := path.Join(aah.AppImportPath(), "app", "controllers")
+	wsPrefix := path.Join(aah.AppImportPath(), "app", "websockets")
+
+	methods := make(map[string]map[string]*ainsp.MethodInfo)
+	for _, t := range types {
+		m := make(map[string]*ainsp.MethodInfo)
+		for _, method := range t.Methods {
+			m[method.Name] = method
+		}
+
+		methods[t.Name] = m
+		ns := strings.TrimPrefix(strings.TrimPrefix(t.ImportPath, controllersPrefix), wsPrefix)
+		if ns = strings.Trim(ns, "/"); !ess.IsStrEmpty(ns) {
+			methods[ns+"/"+t.Name] = m
+		}
+	}
+	return methods
+}
+
+func lookupActionMethod(methods map[string]map[string]*ainsp.MethodInfo, controller, action string) *ainsp.MethodInfo {
+	m, found := methods[controller]
+	if !found {
+		if m, found = methods[path.Base(strings.Replace(controller, ".", "/", -1))]; !found {
+			return nil
+		}
+	}
+	return m[action]
+}
+
+func paramTypeName(p *ainsp.ParameterInfo) string {
+	if p.Type == nil {
+		return ""
+	}
+	return p.Type.Name
+}
+
+// matchFilter method reports whether value matches the filter pattern.
+// Wildcard '*' matches across '/' unlike 'path.Match', so '/api/*' matches
+// nested paths too.
+func matchFilter(pattern, value string) bool {
+	if ess.IsStrEmpty(pattern) {
+		return true
+	}
+	if strings.ContainsAny(pattern, "*?") {
+		var expr strings.Builder
+		expr.WriteString("^")
+		for _, r := range pattern {
+			switch r {
+			case '*':
+				expr.WriteString(".*")
+			case '?':
+				expr.WriteString(".")
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		expr.WriteString("$")
+		return regexp.MustCompile(expr.String()).MatchString(value)
+	}
+	return strings.Contains(value, pattern)
+}
+
+func printRouteTable(routes []*routeInfo) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "DOMAIN\tMETHOD\tPATH\tNAME\tCONTROLLER.ACTION\tPARAMETERS\tAUTH\t")
+	var missing int
+	for _, r := range routes {
+		target := "static"
+		if !r.Static {
+			target = r.Controller + "." + r.Action
+		}
+		if !r.Implemented {
+			target += " (not implemented)"
+			missing++
+		}
+
+		var params []string
+		for _, p := range r.Parameters {
+			params = append(params, p.Name+" "+p.Type)
+		}
+
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", r.Domain, r.Method, r.Path, r.Name,
+			target, firstNonEmpty(strings.Join(params, ", "), "-"), firstNonEmpty(r.Auth, "-"))
+	}
+	_ = tw.Flush()
+
+	fmt.Printf("\n%d route(s)", len(routes))
+	if missing > 0 {
+		fmt.Printf(", %d not implemented", missing)
+	}
+	fmt.Println()
+}
@@ -50,6 +50,13 @@ var runCmd = cli.Command{
 		aah run --importpath github.com/user/appname --envprofile qa
 		aah run --importpath github.com/user/appname --envprofile qa --config /path/to/config/external.conf
 
+	Race detector and coverage modes:
+		aah run --race
+		aah run --cover
+
+	In coverage mode, each run (including hot-reload restarts) writes coverage profile on graceful
+	shutdown and those are merged into 'build/.cover/coverage.out' when 'aah run' exits.
+
 	Note: For production use, it is recommended to follow build and deploy approach instead of
 	using 'aah run'.`,
 	Flags: []cli.Flag{
@@ -64,6 +71,15 @@ var runCmd = cli.Command{
 			Name:  "c, config",
 			Usage: "External config file for overriding aah.conf values",
 		},
+		cli.BoolFlag{
+			Name:  "race",
+			Usage: "Builds the application with Go race detector enabled",
+		},
+		cli.BoolFlag{
+			Name:  "cover",
+			Usage: "Builds the application with coverage enabled, profiles are merged on exit",
+		},
+		diagFormatFlag,
 	},
 	Action: runAction,
 }
@@ -72,6 +88,8 @@ type (
 	hotReload struct {
 		ChangedOrError bool
 		IsSSL          bool
+		Race           bool
+		Cover          bool
 		ProxyPort      string
 		BaseDir        string
 		Addr           string
@@ -87,8 +105,9 @@ type (
 	}
 
 	process struct {
-		cmd *exec.Cmd
-		nw  *notifyWriter
+		cmd    *exec.Cmd
+		nw     *notifyWriter
+		exited <-chan bool
 	}
 
 	notifyWriter struct {
@@ -126,6 +145,20 @@ func runAction(c *cli.Context) error {
 		envProfile = aah.AppProfile()
 	}
 
+	race, cover := c.Bool("race"), c.Bool("cover")
+	if cover {
+		// 'go build -cover' and GOCOVERDIR are available since go1.20
+		checkGoVersion("1.20", "Coverage mode '--cover'")
+
+		// Go runtime writes coverage data into GOCOVERDIR, application
+		// process inherits it from CLI
+		coverDir := prepareCoverDir(aah.AppBaseDir())
+		if err := os.Setenv("GOCOVERDIR", coverDir); err != nil {
+			logFatal(err)
+		}
+		cliLog.Infof("Coverage enabled, profiles are written to %s", coverDir)
+	}
+
 	// Hot-Reload is applicable only to `dev` environment profile.
 	if projectCfg.BoolDefault("hot_reload.enable", true) && envProfile == "dev" {
 		cliLog.Infof("Hot-Reload enabled for environment profile: %s", aah.AppProfile())
@@ -150,6 +183,8 @@ func runAction(c *cli.Context) error {
 			Args:          appStartArgs,
 			Proxy:         httputil.NewSingleHostReverseProxy(appURL),
 			ProjectConfig: projectCfg,
+			Race:          race,
+			Cover:         cover,
 		}
 
 		appHotReload.Start()
@@ -163,15 +198,27 @@ func runAction(c *cli.Context) error {
 		ProjectCfg: projectCfg,
 		AppPack:    false,
 		AppEmbed:   false,
+		AppRace:    race,
+		AppCover:   cover,
 	})
 	if err != nil {
-		logFatal(err)
+		logFatalDiag(c, err)
+	}
+
+	if cover {
+		// Application process receives the interrupt too, CLI stays till
+		// application writes the coverage profile
+		signal.Notify(make(chan os.Signal, 1), os.Interrupt, syscall.SIGTERM)
 	}
 
 	if _, err := execCmd(appBinary, appStartArgs, true); err != nil {
 		logFatal(err)
 	}
 
+	if cover {
+		mergeCoverProfiles(aah.AppBaseDir())
+	}
+
 	return nil
 }
 
@@ -212,6 +259,10 @@ func (hr *hotReload) Start() {
 	signal.Notify(sc, os.Interrupt, syscall.SIGTERM)
 	<-sc
 	hr.Stop()
+
+	if hr.Cover {
+		mergeCoverProfiles(hr.BaseDir)
+	}
 }
 
 func (hr *hotReload) CompileAndStart() error {
@@ -221,6 +272,8 @@ func (hr *hotReload) CompileAndStart() error {
 		ProjectCfg: hr.ProjectConfig,
 		AppPack:    false,
 		AppEmbed:   false,
+		AppRace:    hr.Race,
+		AppCover:   hr.Cover,
 	})
 	if err != nil {
 		return err
@@ -247,6 +300,10 @@ func (hr *hotReload) CompileAndStart() error {
 
 func (hr *hotReload) Stop() {
 	hr.Process.Stop()
+	if hr.Cover {
+		// coverage profile is written after graceful shutdown, so wait for it
+		hr.Process.WaitExit(5 * time.Second)
+	}
 }
 
 func (hr *hotReload) RefreshWatcher() {
@@ -421,6 +478,45 @@ func loadWatchFiles(projectCfg *config.Config, baseDir string, w *watcher.Watche
 	}
 }
 
+// prepareCoverDir method creates fresh directory for coverage data of
+// current 'aah run' session i.e. 'build/.cover/raw'.
+func prepareCoverDir(appBaseDir string) string {
+	coverDir := filepath.Join(appBaseDir, "build", buildCoverDirName, "raw")
+	ess.DeleteFiles(coverDir)
+	if err := ess.MkDirAll(coverDir, permRWXRXRX); err != nil {
+		logFatal(err)
+	}
+	return coverDir
+}
+
+// mergeCoverProfiles method merges the coverage data written by every run of
+// the session (hot-reload restarts too) into 'build/.cover/coverage.out' and
+// prints the per function coverage report.
+func mergeCoverProfiles(appBaseDir string) {
+	coverBaseDir := filepath.Join(appBaseDir, "build", buildCoverDirName)
+	coverDir := filepath.Join(coverBaseDir, "raw")
+	if files, _ := filepath.Glob(filepath.Join(coverDir, "covcounters.*")); len(files) == 0 {
+		cliLog.Warn("No coverage profile found, possibly application did not shutdown gracefully")
+		return
+	}
+
+	profile := filepath.Join(coverBaseDir, "coverage.out")
+	if _, err := execCmd(gocmd, []string{"tool", "covdata", "textfmt", "-i=" + coverDir, "-o", profile}, false); err != nil {
+		logErrorf("Unable to merge coverage profiles: %s", err)
+		return
+	}
+
+	report, err := execCmd(gocmd, []string{"tool", "cover", "-func=" + profile}, false)
+	if err != nil {
+		logErrorf("Unable to create coverage report: %s", err)
+		return
+	}
+
+	fmt.Print(report)
+	cliLog.Infof("Coverage profile: %s", profile)
+	cliLog.Infof("To view in browser: go tool cover -html=%s", profile)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // process methods
 //___________________________________
@@ -433,11 +529,12 @@ func (p *process) Start() error {
 		return err
 	}
 
+	p.exited = p.processWait()
 	for {
 		select {
 		case <-p.nw.notify:
 			return nil
-		case <-p.processWait():
+		case <-p.exited:
 			return errors.New("aah application did not start")
 		}
 	}
@@ -471,11 +568,22 @@ func (p *process) Stop() {
 	}
 }
 
+// WaitExit method waits for the process to exit or till given timeout.
+func (p *process) WaitExit(timeout time.Duration) {
+	if p.exited == nil {
+		return
+	}
+	select {
+	case <-p.exited:
+	case <-time.After(timeout):
+	}
+}
+
 func (p *process) processWait() <-chan bool {
 	wait := make(chan bool)
 	go func() {
 		_ = p.cmd.Wait()
-		wait <- true
+		close(wait)
 	}()
 	return wait
 }
@@ -536,22 +536,47 @@ func waitForConnReady(port string) {
 	}
 }
 
+// cleanupAutoGenFiles method cleans the aah.go and build directory except
+// build cache and coverage directory.
 func cleanupAutoGenFiles(appBaseDir string) {
 	appMainGoFile := filepath.Join(appBaseDir, "app", "aah.go")
 	appBuildDir := filepath.Join(appBaseDir, "build")
 	cliLog.Debugf("Cleaning %s", appMainGoFile)
 	cliLog.Debugf("Cleaning build directory %s", appBuildDir)
-	ess.DeleteFiles(appMainGoFile, appBuildDir)
+	ess.DeleteFiles(appMainGoFile)
+
+	buildFiles, _ := filepath.Glob(filepath.Join(appBuildDir, "*"))
+	for _, f := range buildFiles {
+		if name := filepath.Base(f); name == buildCacheDirName || name == buildCoverDirName {
+			continue
+		}
+		ess.DeleteFiles(f)
+	}
 }
 
 func cleanupAutoGenVFSFiles(appBaseDir string) {
 	vfsFiles, _ := filepath.Glob(filepath.Join(appBaseDir, "app", "aah_*_vfs.go"))
+	blobFiles, _ := filepath.Glob(filepath.Join(appBaseDir, "app", "aah_*_vfs.bin"))
+	vfsFiles = append(vfsFiles, blobFiles...)
 	if len(vfsFiles) > 0 {
 		cliLog.Debugf("Cleaning embed files %s", strings.Join(vfsFiles, "\n\t"))
 		ess.DeleteFiles(vfsFiles...)
 	}
 }
 
+// cleanupStaleVFSFiles method removes auto generated VFS files which are not
+// part of current generation, for e.g.: removed mount point.
+func cleanupStaleVFSFiles(appBaseDir string, generated []string) {
+	vfsFiles, _ := filepath.Glob(filepath.Join(appBaseDir, "app", "aah_*_vfs.go"))
+	blobFiles, _ := filepath.Glob(filepath.Join(appBaseDir, "app", "aah_*_vfs.bin"))
+	for _, f := range append(vfsFiles, blobFiles...) {
+		if !ess.IsSliceContainsString(generated, f) {
+			cliLog.Debugf("Cleaning stale embed file %s", f)
+			ess.DeleteFiles(f)
+		}
+	}
+}
+
 func toLowerCamelCase(v string) string {
 	var st []byte
 	for idx := 0; idx < len(v); idx++ {
@@ -74,3 +74,47 @@ func goVersion() string {
 	}
 	return ""
 }
+
+// checkGoVersion method exits the CLI if installed Go version is older than
+// minimum version required by the feature.
+func checkGoVersion(minVer, feature string) {
+	ver := goVersion()
+	if !isGoVersionAtLeast(ver, minVer) {
+		logFatalf("%s requires go%s or later, found go version '%s'", feature, minVer, firstNonEmpty(ver, "unknown"))
+	}
+}
+
+// isGoVersionAtLeast method compares major and minor of Go version e.g.
+// '1.16.3', development version is always satisfied.
+func isGoVersionAtLeast(ver, minVer string) bool {
+	if strings.HasPrefix(ver, "devel") {
+		return true
+	}
+	if ess.IsStrEmpty(ver) {
+		return false
+	}
+
+	v, m := goVersionParts(ver), goVersionParts(minVer)
+	if v[0] != m[0] {
+		return v[0] > m[0]
+	}
+	return v[1] >= m[1]
+}
+
+// goVersionParts method returns the major and minor number of Go version,
+// pre-release suffix e.g. '21rc1' is ignored.
+func goVersionParts(ver string) [2]int {
+	var parts [2]int
+	for i, p := range strings.SplitN(ver, ".", 3) {
+		if i > 1 {
+			break
+		}
+		for _, r := range p {
+			if r < '0' || r > '9' {
+				break
+			}
+			parts[i] = parts[i]*10 + int(r-'0')
+		}
+	}
+	return parts
+}
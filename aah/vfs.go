@@ -0,0 +1,471 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/essentials.v0"
+)
+
+// VFS blob index trailer layout:
+//
+//	<file data><index JSON><index JSON length, 8 bytes big endian><magic>
+const vfsIndexMagic = "AAHVFSIX"
+
+var vfsCmd = cli.Command{
+	Name:  "vfs",
+	Usage: "Inspects embedded files (VFS) of aah application binary",
+	Description: `Command vfs lists, prints and extracts the files embedded into aah application
+	binary (single binary mode) and diff them against the working tree. Binary must be
+	built by aah CLI that writes VFS index, compressed binaries (e.g. upx) are not supported.
+
+	To know more about available 'vfs' sub commands:
+		aah h vfs
+		aah help vfs
+
+	Examples:
+		aah vfs ls build/bin/aahwebsite
+		aah vfs cat build/bin/aahwebsite /static/css/aah.css
+		aah vfs extract -o /tmp/aahwebsite-vfs build/bin/aahwebsite
+		aah vfs diff build/bin/aahwebsite
+		aah vfs diff --basedir /home/user/go/src/github.com/user/aahwebsite aahwebsite
+`,
+	Subcommands: []cli.Command{
+		cli.Command{
+			Name:      "ls",
+			Usage:     "Lists embedded directories and files with sizes and encodings",
+			ArgsUsage: "<binary> [path-prefix]",
+			Action:    vfsListAction,
+		},
+		cli.Command{
+			Name:      "cat",
+			Usage:     "Prints the decompressed content of embedded file",
+			ArgsUsage: "<binary> <path>",
+			Action:    vfsCatAction,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "e, encoding",
+					Usage: "Reads from precompressed variant such as 'br', 'zstd' instead of primary content",
+				},
+			},
+		},
+		cli.Command{
+			Name:      "extract",
+			Usage:     "Extracts the decompressed embedded files into directory",
+			ArgsUsage: "<binary> [path-prefix]",
+			Action:    vfsExtractAction,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "o, output",
+					Usage: "Output directory; the default is '<binary>_vfs' in current directory",
+				},
+			},
+		},
+		cli.Command{
+			Name:      "diff",
+			Usage:     "Diffs the embedded files against the working tree",
+			ArgsUsage: "<binary>",
+			Action:    vfsDiffAction,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "b, basedir",
+					Usage: "Application base directory of the working tree; the default is current directory",
+				},
+			},
+		},
+	},
+}
+
+// vfsIndex holds the mount point info and its entries, it gets written at
+// the end of VFS blob.
+type vfsIndex struct {
+	MountPath    string            `json:"mount_path"`
+	PhysicalPath string            `json:"physical_path"`
+	Excludes     ess.Excludes      `json:"excludes"`
+	Overlays     map[string]string `json:"overlays,omitempty"`
+	DataSize     int64             `json:"data_size"`
+	Entries      []*vfsIndexEntry  `json:"entries"`
+
+	data []byte
+}
+
+// vfsIndexEntry holds the directory or file info of mount point. File
+// content is located at offset of the blob data.
+type vfsIndexEntry struct {
+	Path     string             `json:"path"`
+	Dir      bool               `json:"dir,omitempty"`
+	Size     int64              `json:"size"`
+	Time     time.Time          `json:"time"`
+	Offset   int64              `json:"offset"`
+	Length   int64              `json:"length"`
+	Encoding string             `json:"encoding,omitempty"`
+	Hash     string             `json:"sha256,omitempty"`
+	Variants []*vfsIndexVariant `json:"variants,omitempty"`
+}
+
+// vfsIndexVariant holds the precompressed variant location of file.
+type vfsIndexVariant struct {
+	Encoding string `json:"encoding"`
+	Offset   int64  `json:"offset"`
+	Length   int64  `json:"length"`
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// VFS Subcommand - ls, cat, extract, diff
+//___________________________________
+
+func vfsListAction(c *cli.Context) error {
+	indexes := vfsIndexesFromArgs(c)
+	prefix := c.Args().Get(1)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "MODE\tSIZE\tSTORED\tENCODING\tMODIFIED\tPATH")
+	for _, idx := range indexes {
+		for _, e := range idx.Entries {
+			if !strings.HasPrefix(e.Path, prefix) {
+				continue
+			}
+
+			if e.Dir {
+				_, _ = fmt.Fprintf(tw, "d\t-\t-\t-\t%s\t%s\n", e.Time.Format(time.RFC3339), e.Path)
+				continue
+			}
+
+			encodings := []string{firstNonEmpty(e.Encoding, "identity")}
+			for _, v := range e.Variants {
+				encodings = append(encodings, fmt.Sprintf("%s(%d)", v.Encoding, v.Length))
+			}
+			_, _ = fmt.Fprintf(tw, "-\t%d\t%d\t%s\t%s\t%s\n", e.Size, e.Length,
+				strings.Join(encodings, ","), e.Time.Format(time.RFC3339), e.Path)
+		}
+	}
+	return tw.Flush()
+}
+
+func vfsCatAction(c *cli.Context) error {
+	indexes := vfsIndexesFromArgs(c)
+	fpath := c.Args().Get(1)
+	if ess.IsStrEmpty(fpath) {
+		_ = cli.ShowSubcommandHelp(c)
+		return nil
+	}
+
+	idx, e := findVFSEntry(indexes, fpath)
+	if e == nil {
+		logFatalf("File '%s' not found in embedded files", fpath)
+	}
+	if e.Dir {
+		logFatalf("'%s' is a directory", fpath)
+	}
+
+	r, err := idx.open(e, firstNonEmpty(c.String("e"), c.String("encoding")))
+	if err != nil {
+		logFatal(err)
+	}
+	defer ess.CloseQuietly(r)
+
+	if _, err = io.Copy(os.Stdout, r); err != nil {
+		logFatal(err)
+	}
+	return nil
+}
+
+func vfsExtractAction(c *cli.Context) error {
+	indexes := vfsIndexesFromArgs(c)
+	prefix := c.Args().Get(1)
+	outDir := getNonEmptyAbsPath(c.String("o"), c.String("output"))
+	if ess.IsStrEmpty(outDir) {
+		outDir, _ = filepath.Abs(filepath.Base(c.Args().First()) + "_vfs")
+	}
+
+	cliLog.Infof("Extracting embedded files into '%s'", outDir)
+	var cnt int
+	for _, idx := range indexes {
+		for _, e := range idx.Entries {
+			if e.Dir || !strings.HasPrefix(e.Path, prefix) {
+				continue
+			}
+
+			dst := filepath.Join(outDir, filepath.FromSlash(path.Clean("/"+e.Path)))
+			if err := extractVFSFile(idx, e, dst); err != nil {
+				logFatalf("Unable to extract '%s': %s", e.Path, err)
+			}
+			cliLog.Debugf("|-- Extracted: %s", e.Path)
+			cnt++
+		}
+	}
+
+	cliLog.Infof("Extracted %d file(s)\n", cnt)
+	return nil
+}
+
+func vfsDiffAction(c *cli.Context) error {
+	indexes := vfsIndexesFromArgs(c)
+	baseDir := getNonEmptyAbsPath(c.String("b"), c.String("basedir"))
+	if ess.IsStrEmpty(baseDir) {
+		baseDir = getNonEmptyAbsPath(".", "")
+	}
+
+	var changes int
+	for _, idx := range indexes {
+		proot := vfsRebase(baseDir, idx.PhysicalPath)
+		overlays := make(map[string]string)
+		for k, v := range idx.Overlays {
+			overlays[vfsRebase(proot, k)] = vfsRebase(baseDir, v)
+		}
+		if !ess.IsFileExists(proot) {
+			logErrorf("Mount '%s' physical path '%s' does not exists, use '--basedir' to rebase", idx.MountPath, proot)
+			changes++
+			continue
+		}
+
+		entries, err := walkMount(idx.MountPath, proot, idx.Excludes, overlays)
+		if err != nil {
+			logFatal(err)
+		}
+
+		working := make(map[string]*vfsEntry)
+		for _, e := range entries {
+			working[e.MountPath] = e
+		}
+
+		for _, ie := range idx.Entries {
+			we, found := working[ie.Path]
+			delete(working, ie.Path)
+			switch {
+			case !found:
+				fmt.Printf("D %s\n", ie.Path)
+				changes++
+			case ie.Dir || we.Info.IsDir():
+				if ie.Dir != we.Info.IsDir() {
+					fmt.Printf("M %s\n", ie.Path)
+					changes++
+				}
+			default:
+				hash, err := fileHash(we.Path)
+				if err != nil {
+					logFatal(err)
+				}
+				if hash != ie.Hash {
+					fmt.Printf("M %s\n", ie.Path)
+					changes++
+				}
+			}
+		}
+
+		for _, we := range entries {
+			if _, found := working[we.MountPath]; found {
+				fmt.Printf("A %s\n", we.MountPath)
+				changes++
+			}
+		}
+	}
+
+	if changes > 0 {
+		exit(1)
+	}
+	cliLog.Info("Embedded files are identical to working tree")
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// writeVFSIndex method writes the index trailer into given blob writer.
+func writeVFSIndex(w io.Writer, index *vfsIndex) error {
+	b, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(b)))
+	for _, p := range [][]byte{b, size, []byte(vfsIndexMagic)} {
+		if _, err = w.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readVFSIndexes method scans the given content (aah application binary) for
+// VFS index trailers and returns the parsed indexes.
+func readVFSIndexes(b []byte) []*vfsIndex {
+	var indexes []*vfsIndex
+	magic := []byte(vfsIndexMagic)
+	for pos := 0; ; {
+		i := bytes.Index(b[pos:], magic)
+		if i == -1 {
+			break
+		}
+		end := pos + i
+		pos = end + len(magic)
+
+		if idx := parseVFSIndex(b, end); idx != nil {
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes
+}
+
+// parseVFSIndex method parses the index which ends at given magic position,
+// it returns nil if the bytes are not a valid index.
+func parseVFSIndex(b []byte, magicPos int) *vfsIndex {
+	if magicPos < 8 {
+		return nil
+	}
+
+	// length is validated before conversion, magic string may appear in any
+	// binary and preceding bytes are not a valid length
+	size := binary.BigEndian.Uint64(b[magicPos-8 : magicPos])
+	if size == 0 || size > uint64(magicPos-8) {
+		return nil
+	}
+	start := int64(magicPos-8) - int64(size)
+
+	idx := &vfsIndex{}
+	if err := json.Unmarshal(b[start:magicPos-8], idx); err != nil || ess.IsStrEmpty(idx.MountPath) {
+		return nil
+	}
+
+	dataStart := start - idx.DataSize
+	if idx.DataSize < 0 || dataStart < 0 {
+		return nil
+	}
+	idx.data = b[dataStart:start]
+	return idx
+}
+
+// open method returns the decompressed content reader of given entry, for
+// non-empty encoding the precompressed variant is used.
+func (idx *vfsIndex) open(e *vfsIndexEntry, encoding string) (io.ReadCloser, error) {
+	offset, length, enc := e.Offset, e.Length, e.Encoding
+	if !ess.IsStrEmpty(encoding) && encoding != enc {
+		var found bool
+		for _, v := range e.Variants {
+			if v.Encoding == encoding {
+				offset, length, enc, found = v.Offset, v.Length, v.Encoding, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("'%s' does not have '%s' encoded variant", e.Path, encoding)
+		}
+	}
+
+	if offset < 0 || offset+length > int64(len(idx.data)) {
+		return nil, fmt.Errorf("'%s' data is out of range, binary may be corrupted", e.Path)
+	}
+	r := bytes.NewReader(idx.data[offset : offset+length])
+
+	switch enc {
+	case "":
+		return ioutil.NopCloser(r), nil
+	case codecGzip:
+		return gzip.NewReader(r)
+	case codecBrotli:
+		return ioutil.NopCloser(brotli.NewReader(r)), nil
+	case codecZstd:
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}
+	return nil, fmt.Errorf("'%s' has unsupported encoding '%s'", e.Path, enc)
+}
+
+func vfsIndexesFromArgs(c *cli.Context) []*vfsIndex {
+	cliLog = initCLILogger(nil)
+
+	binaryFile := c.Args().First()
+	if ess.IsStrEmpty(binaryFile) {
+		_ = cli.ShowSubcommandHelp(c)
+		exit(1)
+	}
+
+	b, err := ioutil.ReadFile(binaryFile)
+	if err != nil {
+		logFatal(err)
+	}
+
+	indexes := readVFSIndexes(b)
+	if len(indexes) == 0 {
+		logFatalf("No embedded files found in '%s', it may not be built in single binary mode", binaryFile)
+	}
+	return indexes
+}
+
+// vfsRebase method returns the slash separated path of VFS index relative
+// path on given base directory, absolute path is returned as-is.
+func vfsRebase(baseDir, p string) string {
+	if filepath.IsAbs(filepath.FromSlash(p)) {
+		return p
+	}
+	return filepath.ToSlash(filepath.Join(baseDir, filepath.FromSlash(p)))
+}
+
+func findVFSEntry(indexes []*vfsIndex, fpath string) (*vfsIndex, *vfsIndexEntry) {
+	fpath = path.Clean("/" + fpath)
+	for _, idx := range indexes {
+		for _, e := range idx.Entries {
+			if e.Path == fpath {
+				return idx, e
+			}
+		}
+	}
+	return nil, nil
+}
+
+func extractVFSFile(idx *vfsIndex, e *vfsIndexEntry, dst string) error {
+	if err := ess.MkDirAll(filepath.Dir(dst), permRWXRXRX); err != nil {
+		return err
+	}
+
+	r, err := idx.open(e, "")
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(r)
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	ess.CloseQuietly(f)
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(dst, e.Time, e.Time)
+}
+
+func fileHash(fpath string) (string, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return "", err
+	}
+	defer ess.CloseQuietly(f)
+	return contentHash(f)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseVFSIndex(t *testing.T) {
+	data := []byte("file data")
+	buf := &bytes.Buffer{}
+	buf.Write(data)
+	if err := writeVFSIndex(buf, &vfsIndex{MountPath: "/app", DataSize: int64(len(data))}); err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+	magicPos := len(valid) - len(vfsIndexMagic)
+
+	trailer := func(prefix []byte, size uint64) []byte {
+		b := append([]byte{}, prefix...)
+		sb := make([]byte, 8)
+		binary.BigEndian.PutUint64(sb, size)
+		return append(append(b, sb...), vfsIndexMagic...)
+	}
+
+	testcases := []struct {
+		label    string
+		input    []byte
+		magicPos int
+		valid    bool
+	}{
+		{"valid index", valid, magicPos, true},
+		{"huge length", trailer([]byte("{}"), ^uint64(0)), 10, false},
+		{"length beyond start", trailer([]byte("{}"), 3), 10, false},
+		{"zero length", trailer([]byte("{}"), 0), 10, false},
+		{"truncated index", valid[len(data)+5:], magicPos - len(data) - 5, false},
+		{"stray magic at start", []byte(vfsIndexMagic + "some binary"), 0, false},
+		{"stray magic in binary", []byte("\x7fELF\x02\x01\x01\x00\x00\x00" + vfsIndexMagic), 10, false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			idx := parseVFSIndex(tc.input, tc.magicPos)
+			if tc.valid != (idx != nil) {
+				t.Fatalf("expected valid=%v, got index %v", tc.valid, idx)
+			}
+			if idx != nil && (idx.MountPath != "/app" || string(idx.data) != string(data)) {
+				t.Errorf("unexpected index: %s %q", idx.MountPath, idx.data)
+			}
+		})
+	}
+
+	if indexes := readVFSIndexes(append([]byte(vfsIndexMagic), valid...)); len(indexes) != 1 {
+		t.Errorf("expected 1 index, got %d", len(indexes))
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// viewFuncNames is the template functions provided by aah framework to the
+// view engine.
+var viewFuncNames = []string{
+	"config", "i18n", "rurl", "rurlm", "pparam", "fparam", "qparam",
+	"session", "flash", "isauthenticated", "hasrole", "hasallroles",
+	"hasanyrole", "ispermitted", "ispermittedall", "anticsrftoken",
+	"safeHTML", "import", "include",
+}
+
+// viewValidator validates the view templates of 'go' view engine, it
+// parses the templates with configured delimiters and function names.
+type viewValidator struct {
+	baseDir       string
+	viewsDir      string
+	ext           string
+	leftDelim     string
+	rightDelim    string
+	defaultLayout bool
+	funcs         template.FuncMap
+	diags         []*diagnostic
+}
+
+// viewTemplate is the parsed view file.
+type viewTemplate struct {
+	file    string
+	trees   map[string]*parse.Tree
+	defines map[string]bool
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// validateViews method parses every view file under 'views' and reports the
+// parse errors, undefined templates, missing common imports and layout
+// references with file and line position. View engine other than 'go' is
+// not validated.
+func validateViews(appBaseDir string, appCfg, projectCfg *config.Config) []*diagnostic {
+	viewsDir := filepath.Join(appBaseDir, "views")
+	if !ess.IsFileExists(viewsDir) {
+		return nil
+	}
+
+	if engine := appCfg.StringDefault("view.engine", "go"); engine != "go" {
+		cliLog.Infof("View validation is skipped for view engine '%s'", engine)
+		return nil
+	}
+
+	delims := strings.Split(appCfg.StringDefault("view.delimiters", "{{.}}"), ".")
+	if len(delims) != 2 || ess.IsStrEmpty(delims[0]) || ess.IsStrEmpty(delims[1]) {
+		return []*diagnostic{{Origin: diagOriginView, File: "config/aah.conf", Severity: diagError,
+			Message: "config 'view.delimiters' value is not valid"}}
+	}
+
+	v := &viewValidator{
+		baseDir:       appBaseDir,
+		viewsDir:      viewsDir,
+		ext:           appCfg.StringDefault("view.ext", ".html"),
+		leftDelim:     delims[0],
+		rightDelim:    delims[1],
+		defaultLayout: appCfg.BoolDefault("view.default_layout", true),
+		funcs:         template.FuncMap{},
+	}
+
+	funcNames := append([]string{}, viewFuncNames...)
+	if projectCfg.BoolDefault("build.assets.enable", false) {
+		// registered by generated main only if asset pipeline is enabled
+		funcNames = append(funcNames, "asset")
+	}
+	funcNames = append(funcNames, appTemplateFuncNames(filepath.Join(appBaseDir, "app"))...)
+	if names, found := projectCfg.StringList("build.views.funcs"); found {
+		funcNames = append(funcNames, names...)
+	}
+	for _, name := range funcNames {
+		v.funcs[name] = func(...interface{}) interface{} { return nil }
+	}
+
+	layouts := v.parseDir("layouts")
+	pages := v.parseDir("pages")
+	_ = v.parseDir("common")
+	_ = v.parseDir("errors")
+
+	v.checkLayoutRefs(filepath.Join(appBaseDir, "app", "controllers"), layouts)
+
+	// pages are rendered with default layout 'master<ext>' if enabled or with
+	// layout given in controller, page is not linked to its layout statically
+	// so the default layout mismatches are reported as warnings
+	var layout *viewTemplate
+	if v.defaultLayout {
+		name := "master" + v.ext
+		if layout = layouts[name]; layout == nil && len(layouts) > 0 {
+			v.layoutErrorf(filepath.Join(appBaseDir, "config", "aah.conf"), "view.default_layout", name)
+		}
+	}
+
+	var allLayouts []*viewTemplate
+	for _, name := range sortedViewNames(layouts) {
+		allLayouts = append(allLayouts, layouts[name])
+	}
+
+	for _, name := range sortedViewNames(pages) {
+		page := pages[name]
+		if layout != nil {
+			v.checkTemplateRefs(layout, []*viewTemplate{page}, diagWarning, "default layout for page "+v.relPath(page.file))
+		}
+		v.checkTemplateRefs(page, allLayouts, diagError, "")
+	}
+
+	sortDiagnostics(v.diags)
+	return v.diags
+}
+
+// parseDir method parses the view files of given views sub directory and
+// returns the parsed views by relative path.
+func (v *viewValidator) parseDir(name string) map[string]*viewTemplate {
+	views := make(map[string]*viewTemplate)
+	dir := filepath.Join(v.viewsDir, name)
+	files, _ := ess.FilesPath(dir, true)
+	for _, f := range files {
+		if filepath.Ext(f) != v.ext {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			v.diags = append(v.diags, &diagnostic{Origin: diagOriginView, File: v.relPath(f),
+				Severity: diagError, Message: err.Error()})
+			continue
+		}
+
+		tmpl, err := template.New(f).Delims(v.leftDelim, v.rightDelim).Funcs(v.funcs).Parse(string(b))
+		if err != nil {
+			v.diags = append(v.diags, diagnosticsFromErrors(diagOriginView,
+				[]error{fmt.Errorf("%s", strings.TrimPrefix(err.Error(), "template: "))}, v.baseDir)...)
+			continue
+		}
+
+		vt := &viewTemplate{file: f, trees: map[string]*parse.Tree{}, defines: map[string]bool{}}
+		for _, t := range tmpl.Templates() {
+			if t.Tree == nil {
+				continue
+			}
+			vt.trees[t.Name()] = t.Tree
+			if t.Name() != f {
+				vt.defines[t.Name()] = true
+			}
+		}
+		v.checkImports(vt)
+
+		rel, _ := filepath.Rel(dir, f)
+		views[filepath.ToSlash(rel)] = vt
+	}
+	return views
+}
+
+// checkTemplateRefs method reports the '{{ template "name" }}' references of
+// view which are not defined in the view or in any of its companion views.
+func (v *viewValidator) checkTemplateRefs(vt *viewTemplate, companions []*viewTemplate, severity, context string) {
+	isDefined := func(name string) bool {
+		if vt.defines[name] {
+			return true
+		}
+		for _, c := range companions {
+			if c.defines[name] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, tree := range vt.trees {
+		walkTemplateNodes(tree.Root, func(n parse.Node) {
+			tn, ok := n.(*parse.TemplateNode)
+			if !ok || isDefined(tn.Name) {
+				return
+			}
+
+			msg := fmt.Sprintf("template %q is not defined", tn.Name)
+			if !ess.IsStrEmpty(context) {
+				msg += " (" + context + ")"
+			}
+			v.reportAt(tree, n, severity, msg)
+		})
+	}
+}
+
+// checkImports method reports the '{{ import "file" . }}' references which
+// does not exist in 'views/common'.
+func (v *viewValidator) checkImports(vt *viewTemplate) {
+	for _, tree := range vt.trees {
+		walkTemplateNodes(tree.Root, func(n parse.Node) {
+			cmd, ok := n.(*parse.CommandNode)
+			if !ok || len(cmd.Args) < 2 {
+				return
+			}
+			ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+			if !ok || (ident.Ident != "import" && ident.Ident != "include") {
+				return
+			}
+			str, ok := cmd.Args[1].(*parse.StringNode)
+			if !ok {
+				return
+			}
+
+			if !ess.IsFileExists(filepath.Join(v.viewsDir, "common", filepath.FromSlash(str.Text))) {
+				v.reportAt(tree, n, diagError, fmt.Sprintf("%s file %q does not exist in 'views/common'", ident.Ident, str.Text))
+			}
+		})
+	}
+}
+
+// checkLayoutRefs method reports the layout given in controller via
+// 'Reply().HTMLl' or 'Reply().HTMLlf' which does not exist in 'views/layouts'.
+func (v *viewValidator) checkLayoutRefs(dir string, layouts map[string]*viewTemplate) {
+	fset := token.NewFileSet()
+	files, _ := ess.FilesPath(dir, true)
+	for _, f := range files {
+		if filepath.Ext(f) != ".go" || strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, f, nil, 0)
+		if err != nil {
+			// Go source errors are reported by inspection and compile
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "HTMLl" && sel.Sel.Name != "HTMLlf") {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+
+			if layout, err := strconv.Unquote(lit.Value); err == nil && layouts[layout] == nil {
+				pos := fset.Position(lit.Pos())
+				v.diags = append(v.diags, &diagnostic{Origin: diagOriginView, File: v.relPath(f),
+					Line: pos.Line, Column: pos.Column, Severity: diagError,
+					Message: fmt.Sprintf("layout %q does not exist in 'views/layouts'", layout),
+					Excerpt: sourceLine(f, pos.Line)})
+			}
+			return true
+		})
+	}
+}
+
+func (v *viewValidator) layoutErrorf(file, key, layout string) {
+	d := &diagnostic{Origin: diagOriginView, File: v.relPath(file), Severity: diagError,
+		Message: fmt.Sprintf("default layout %q does not exist in 'views/layouts'", layout)}
+	if keys, err := scanConfFile(file); err == nil {
+		if d.Line = keys.Line(key); d.Line > 0 {
+			d.Excerpt = sourceLine(file, d.Line)
+		}
+	}
+	v.diags = append(v.diags, d)
+}
+
+func (v *viewValidator) reportAt(tree *parse.Tree, n parse.Node, severity, msg string) {
+	d := &diagnostic{Origin: diagOriginView, File: v.relPath(tree.ParseName), Severity: severity, Message: msg}
+	d.Line, d.Column = templateNodePos(tree, n)
+	d.Excerpt = sourceLine(tree.ParseName, d.Line)
+	v.diags = append(v.diags, d)
+}
+
+func (v *viewValidator) relPath(file string) string {
+	return diagRelPath(v.baseDir, file)
+}
+
+// appTemplateFuncNames method returns the template function names added by
+// application via 'aah.AddTemplateFunc(template.FuncMap{...})'.
+func appTemplateFuncNames(dir string) []string {
+	var names []string
+	fset := token.NewFileSet()
+	files, _ := ess.FilesPath(dir, true)
+	for _, f := range files {
+		if filepath.Ext(f) != ".go" || strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, f, nil, 0)
+		if err != nil {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "AddTemplateFunc" {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.CompositeLit); ok {
+				for _, elt := range lit.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						if key, ok := kv.Key.(*ast.BasicLit); ok && key.Kind == token.STRING {
+							if name, err := strconv.Unquote(key.Value); err == nil {
+								names = append(names, name)
+							}
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	return names
+}
+
+// templateNodePos method returns the line and column of template node.
+func templateNodePos(tree *parse.Tree, n parse.Node) (line, col int) {
+	// ErrorContext returns location as 'name:line:col'
+	location, _ := tree.ErrorContext(n)
+	if idx := strings.LastIndex(location, ":"); idx > 0 {
+		col, _ = strconv.Atoi(location[idx+1:])
+		if lidx := strings.LastIndex(location[:idx], ":"); lidx > 0 {
+			line, _ = strconv.Atoi(location[lidx+1 : idx])
+		}
+	}
+	return
+}
+
+// walkTemplateNodes method calls the fn for node and its child nodes.
+func walkTemplateNodes(n parse.Node, fn func(parse.Node)) {
+	if n == nil {
+		return
+	}
+	fn(n)
+
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, c := range n.Nodes {
+				walkTemplateNodes(c, fn)
+			}
+		}
+	case *parse.ActionNode:
+		walkTemplateNodes(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n != nil {
+			for _, c := range n.Cmds {
+				walkTemplateNodes(c, fn)
+			}
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			walkTemplateNodes(a, fn)
+		}
+	case *parse.IfNode:
+		walkBranchNodes(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranchNodes(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranchNodes(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		walkTemplateNodes(n.Pipe, fn)
+	}
+}
+
+func walkBranchNodes(b *parse.BranchNode, fn func(parse.Node)) {
+	walkTemplateNodes(b.Pipe, fn)
+	walkTemplateNodes(b.List, fn)
+	walkTemplateNodes(b.ElseList, fn)
+}
+
+func sortedViewNames(views map[string]*viewTemplate) []string {
+	var names []string
+	for name := range views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,241 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// aahframework.org/tools/aah source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+)
+
+func generateWebSocketAction(c *cli.Context) error {
+	if c.NArg() == 0 {
+		_ = cli.ShowSubcommandHelp(c)
+		return nil
+	}
+
+	importPath := appImportPath(c)
+	if err := aah.Init(importPath); err != nil {
+		logFatal(err)
+	}
+	projectCfg := aahProjectCfg(aah.AppBaseDir())
+	cliLog = initCLILogger(projectCfg)
+
+	msgType := strings.ToLower(firstNonEmpty(c.String("m"), c.String("message"), "text"))
+	if msgType != "text" && msgType != "json" {
+		logFatalf("Unsupported message type '%s', try one of these 'text', 'json'", msgType)
+	}
+
+	wsh, err := newScaffoldWebSocket(c.Args().First(), msgType)
+	if err != nil {
+		logFatal(err)
+	}
+
+	// websocket handler file
+	destFile := filepath.Join(aah.AppBaseDir(), "app", "websockets", filepath.FromSlash(wsh.ImportDir), wsh.File)
+	if checkAndConfirmOverwrite(c, destFile) {
+		return nil
+	}
+	data := map[string]interface{}{"WS": wsh, "Action": wsh.Actions[0], "EventFunc": strings.TrimSuffix(wsh.Type, "WebSocket") + "Events"}
+	if err = generateScaffoldSource(destFile, aahWebSocketTemplate, data); err != nil {
+		logFatal(err)
+	}
+	cliLog.Infof("Generated WebSocket '%s' at %s", wsh.Ref, stripGoSrcPath(destFile))
+
+	// test page in static directory
+	pageFile := filepath.Join(aah.AppBaseDir(), "static", wsh.Resource+"_ws.html")
+	if !checkAndConfirmOverwrite(c, pageFile) {
+		buf := &bytes.Buffer{}
+		if err = renderTmpl(buf, aahWebSocketTestPageTemplate, data); err != nil {
+			logFatal(err)
+		}
+		if err = ess.MkDirAll(filepath.Dir(pageFile), permRWXRXRX); err != nil {
+			logFatal(err)
+		}
+		if err = ioutil.WriteFile(pageFile, buf.Bytes(), permRWRWRW); err != nil {
+			logFatal(err)
+		}
+		cliLog.Infof("Generated WebSocket test page at %s", stripGoSrcPath(pageFile))
+	}
+
+	// routes.conf wiring
+	routesFile := filepath.Join(aah.AppBaseDir(), "config", "routes.conf")
+	domain := firstNonEmpty(c.String("d"), c.String("domain"))
+	if err = addRoutes(routesFile, domain, wsh.routeEntries()); err != nil {
+		logFatalf("Unable to add routes into 'config/routes.conf': %s", err)
+	}
+
+	verifyScaffold(projectCfg, "websockets", aah.AppRouter().RegisteredWSActions(), libImportPath("ws"), wsh)
+
+	cliLog.Infof("Register the lifecycle events in 'app/init.go':\n\t%s", strings.Join([]string{
+		fmt.Sprintf("aah.AppWSEngine().OnPreConnect(%s.%s)", wsh.Package, data["EventFunc"]),
+		fmt.Sprintf("aah.AppWSEngine().OnPostConnect(%s.%s)", wsh.Package, data["EventFunc"]),
+		fmt.Sprintf("aah.AppWSEngine().OnPostDisconnect(%s.%s)", wsh.Package, data["EventFunc"]),
+		fmt.Sprintf("aah.AppWSEngine().OnError(%s.%s)", wsh.Package, data["EventFunc"]),
+	}, "\n\t"))
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// newScaffoldWebSocket method creates the WebSocket info from name e.g.
+// 'Chat' or 'support/Chat'. It has one message action 'Text' or 'JSON' on
+// route 'WS /ws/<name>'.
+func newScaffoldWebSocket(name, msgType string) (*scaffoldController, error) {
+	name = strings.Trim(filepath.ToSlash(strings.TrimSpace(name)), "/")
+	pkgDir, base := path.Split(name)
+	base = strings.TrimSuffix(base, "WebSocket")
+	if ess.IsStrEmpty(base) {
+		return nil, fmt.Errorf("websocket name '%s' is not valid", name)
+	}
+
+	typeName := camelIdent(base, true)
+	resource := snakeCase(typeName)
+	wsh := &scaffoldController{
+		Package:   "websockets",
+		Type:      typeName + "WebSocket",
+		Resource:  resource,
+		ImportDir: strings.TrimSuffix(pkgDir, "/"),
+		File:      resource + ".go",
+	}
+	wsh.Ref = path.Join(wsh.ImportDir, wsh.Type)
+	wsh.Path = "/ws/" + path.Join(wsh.ImportDir, resource)
+	wsh.RoutePrefix = "ws_" + strings.Replace(path.Join(wsh.ImportDir, resource), "/", "_", -1)
+	if !ess.IsStrEmpty(wsh.ImportDir) {
+		wsh.Package = goPackageName(path.Base(wsh.ImportDir))
+	}
+
+	action := "Text"
+	if msgType == "json" {
+		action = "JSON"
+	}
+	wsh.Actions = []*scaffoldAction{{Name: action, Method: "WS", Path: wsh.Path, RouteName: wsh.RoutePrefix}}
+	return wsh, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// WebSocket Templates
+//___________________________________
+
+const aahWebSocketTemplate = `// Generated by 'aah generate websocket', feel free to customize it.
+
+package {{ .WS.Package }}
+
+import (
+	"aahframework.org/ws.v0"
+)
+
+// {{ .WS.Type }} handles the WebSocket '{{ .WS.Path }}'.
+type {{ .WS.Type }} struct {
+	*ws.Context
+}
+
+// {{ .Action.Name }} method reads the {{ if eq .Action.Name "JSON" }}JSON{{ else }}text{{ end }} messages of WebSocket connection
+// and echoes it back, till the client disconnects.
+func (w *{{ .WS.Type }}) {{ .Action.Name }}() {
+	for {
+		{{ if eq .Action.Name "JSON" -}}
+		var msg map[string]interface{}
+		if err := w.ReadJSON(&msg); err != nil {
+		{{- else -}}
+		msg, err := w.ReadText()
+		if err != nil {
+		{{- end }}
+			if !ws.IsDisconnected(err) {
+				w.Log().Error(err)
+			}
+			return
+		}
+
+		if err := w.Reply{{ if eq .Action.Name "JSON" }}JSON{{ else }}Text{{ end }}(msg); err != nil {
+			w.Log().Error(err)
+		}
+	}
+}
+
+// {{ .EventFunc }} method handles the WebSocket lifecycle events, register it
+// with WebSocket engine in 'app/init.go'.
+func {{ .EventFunc }}(eventName string, ctx *ws.Context) {
+	switch eventName {
+	case ws.EventOnPreConnect:
+		// Authenticate or validate the request here, abort the connection
+		// via 'ctx.Abort(http.StatusUnauthorized)'
+		ctx.Log().Infof("Connecting to %s", ctx.Req.Path)
+	case ws.EventOnPostConnect:
+		ctx.Log().Infof("Connected to %s", ctx.Req.Path)
+	case ws.EventOnPostDisconnect:
+		ctx.Log().Infof("Disconnected from %s", ctx.Req.Path)
+	case ws.EventOnError:
+		ctx.Log().Errorf("Error on %s", ctx.Req.Path)
+	}
+}
+`
+
+const aahWebSocketTestPageTemplate = `<!DOCTYPE html>
+<!-- Generated by 'aah generate websocket', page for manual testing of WebSocket '{{ .WS.Path }}' -->
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>{{ .WS.Type }} - test page</title>
+  <style>
+    body { font-family: sans-serif; margin: 2em; }
+    #log { background: #f4f4f4; padding: 1em; height: 20em; overflow-y: auto; }
+  </style>
+</head>
+<body>
+  <h3>{{ .WS.Type }} <small id="status">connecting...</small></h3>
+  <form id="form">
+    <input id="message" size="60" autocomplete="off" placeholder="{{ if eq .Action.Name "JSON" }}JSON message e.g. {&quot;text&quot;: &quot;hello&quot;}{{ else }}Text message{{ end }}">
+    <button type="submit">Send</button>
+  </form>
+  <pre id="log"></pre>
+  <script>
+    (function () {
+      var log = document.getElementById('log');
+      var status = document.getElementById('status');
+      var input = document.getElementById('message');
+      var scheme = location.protocol === 'https:' ? 'wss://' : 'ws://';
+      var socket = new WebSocket(scheme + location.host + '{{ .WS.Path }}');
+
+      function append(line) {
+        log.textContent += new Date().toLocaleTimeString() + '  ' + line + '\n';
+        log.scrollTop = log.scrollHeight;
+      }
+
+      socket.onopen = function () { status.textContent = 'connected'; };
+      socket.onclose = function (e) { status.textContent = 'disconnected (' + e.code + ')'; };
+      socket.onerror = function () { append('error'); };
+      socket.onmessage = function (e) { append('<< ' + e.data); };
+
+      document.getElementById('form').onsubmit = function (e) {
+        e.preventDefault();
+        var msg = input.value;
+        {{- if eq .Action.Name "JSON" }}
+        try {
+          msg = JSON.stringify(JSON.parse(msg));
+        } catch (err) {
+          append('invalid JSON: ' + err.message);
+          return;
+        }
+        {{- end }}
+        socket.send(msg);
+        append('>> ' + msg);
+        input.value = '';
+      };
+    })();
+  </script>
+</body>
+</html>
+`